@@ -0,0 +1,89 @@
+package rule34
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Pre-defined errors for the autocomplete request.
+var (
+	// ErrEmptyPrefix is returned when an empty prefix is given to Autocomplete.
+	ErrEmptyPrefix = errors.New("autocomplete prefix can't be empty")
+)
+
+// autocompleteFile is the file of the autocomplete endpoint, relative to the directory of the DAPI endpoint.
+const autocompleteFile = "autocomplete.php"
+
+// Autocomplete fetches tag suggestions for the given prefix using a background context.
+// See AutocompleteContext.
+func (c *Client) Autocomplete(prefix string) (Suggestions, error) {
+	return c.AutocompleteContext(context.Background(), prefix)
+}
+
+// AutocompleteContext fetches tag suggestions for the given prefix.
+// Each suggestion carries its label, the tag value and the post count parsed out of the label.
+func (c *Client) AutocompleteContext(ctx context.Context, prefix string) (Suggestions, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return nil, fmt.Errorf("invalid arguments: %w", ErrEmptyPrefix)
+	}
+
+	url, err := c.buildAutocompleteURL(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build url: %w", err)
+	}
+
+	suggestionsBytes, err := c.doRequest(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to do autocomplete request: %w", err)
+	}
+
+	suggestions, err := unmarshalSuggestions(suggestionsBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal suggestions: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// buildAutocompleteURL constructs the autocomplete request URL next to the client's base URL,
+// keeping any path prefix such as a proxy mount point.
+func (c *Client) buildAutocompleteURL(prefix string) (string, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("can't parse base URL: %w", err)
+	}
+
+	dir := path.Dir(u.Path)
+	if dir == "." {
+		dir = "/"
+	}
+	u.Path = path.Join(dir, autocompleteFile)
+
+	q := url.Values{}
+	q.Set("q", prefix)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// unmarshalSuggestions parses the JSON response body into a slice of Suggestion models.
+func unmarshalSuggestions(suggestionsBytes []byte) (Suggestions, error) {
+	if len(suggestionsBytes) == 0 {
+		return Suggestions{}, nil
+	}
+
+	var suggestions Suggestions
+
+	err := json.Unmarshal(suggestionsBytes, &suggestions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal suggestions: %w", err)
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,132 @@
+package rule34
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Pre-defined errors describing error payloads the API returns with HTTP 200.
+var (
+	// ErrAuthRequired is returned when the API asks for authentication, for example
+	// because the credentials are missing or invalid. It wraps ErrUnauthorized, so checking
+	// for ErrUnauthorized covers both rejected requests and authentication payloads.
+	ErrAuthRequired = fmt.Errorf("authentication required: %w", ErrUnauthorized)
+	// ErrSearchLimit is returned when the API refuses a search because it exceeds a limit.
+	ErrSearchLimit = errors.New("search limit exceeded")
+	// ErrUnexpectedResponse is returned when the API answers with a payload that isn't
+	// the expected document, most likely because the API changed.
+	ErrUnexpectedResponse = errors.New("unexpected response")
+)
+
+// errorResponse is the XML document the API returns on failures, for example
+// <response success="false" reason="..."/>.
+type errorResponse struct {
+	XMLName xml.Name `xml:"response"`
+	Success string   `xml:"success,attr"`
+	Reason  string   `xml:"reason,attr"`
+	Message string   `xml:",chardata"`
+}
+
+// classifyResponse checks a successful response body for an error payload. It returns
+// an *APIError if the body is a plain-text or XML error message, or any other document
+// than the expected one, such as an HTML error page, and nil otherwise.
+func classifyResponse(requestURL string, body []byte) error {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	var message string
+	switch trimmed[0] {
+	case '[', '{':
+		return nil
+	case '<':
+		reason, ok := parseErrorResponse(trimmed)
+		if ok {
+			message = reason
+			break
+		}
+
+		if expectsJSON(requestURL) || isHTML(trimmed) {
+			return newResponseError(requestURL, string(trimmed), ErrUnexpectedResponse)
+		}
+
+		return nil
+	default:
+		message = string(trimmed)
+	}
+
+	return newResponseError(requestURL, message, sentinelForMessage(message))
+}
+
+// newResponseError creates an APIError for an error payload returned with HTTP 200.
+func newResponseError(requestURL string, message string, sentinel error) *APIError {
+	return &APIError{
+		StatusCode: http.StatusOK,
+		Endpoint:   endpointOf(requestURL),
+		Snippet:    snippetOf([]byte(message)),
+		Retryable:  errors.Is(sentinel, ErrRateLimited),
+		Err:        sentinel,
+	}
+}
+
+// expectsJSON reports whether the request asks for a JSON response, so any other body is unexpected.
+func expectsJSON(requestURL string) bool {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return false
+	}
+
+	return u.Query().Get("json") == "1" || path.Base(u.Path) == autocompleteFile
+}
+
+// isHTML reports whether the body is an HTML page, such as a proxy error or a challenge page.
+func isHTML(body []byte) bool {
+	lower := bytes.ToLower(body[:min(len(body), 64)])
+	return bytes.HasPrefix(lower, []byte("<!doctype html")) || bytes.HasPrefix(lower, []byte("<html"))
+}
+
+// parseErrorResponse extracts the reason from an XML error response.
+// It reports false if the body is a regular XML document.
+func parseErrorResponse(body []byte) (string, bool) {
+	var resp errorResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return "", false
+	}
+
+	if resp.Success != "" && resp.Success != "false" {
+		return "", false
+	}
+
+	if resp.Reason != "" {
+		return resp.Reason, true
+	}
+
+	return strings.TrimSpace(resp.Message), true
+}
+
+// sentinelForMessage maps an API error message to the sentinel error of its failure kind.
+func sentinelForMessage(message string) error {
+	lower := strings.ToLower(message)
+
+	switch {
+	case strings.Contains(lower, "rate limit"),
+		strings.Contains(lower, "too many requests"):
+		return ErrRateLimited
+	case strings.Contains(lower, "authentication"),
+		strings.Contains(lower, "api key"),
+		strings.Contains(lower, "api_key"),
+		strings.Contains(lower, "user_id"):
+		return ErrAuthRequired
+	case strings.Contains(lower, "limit"):
+		return ErrSearchLimit
+	default:
+		return ErrUnexpectedResponse
+	}
+}
@@ -0,0 +1,74 @@
+package rule34
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassifyResponse(t *testing.T) {
+	const (
+		jsonURL         = "https://api.rule34.xxx/index.php?page=dapi&s=post&q=index&json=1"
+		xmlURL          = "https://api.rule34.xxx/index.php?page=dapi&s=tag&q=index"
+		autocompleteURL = "https://api.rule34.xxx/autocomplete.php?q=cat"
+		htmlPage        = "<!DOCTYPE html><html><head><title>502 Bad Gateway</title></head></html>"
+	)
+
+	tests := []struct {
+		name          string
+		url           string
+		body          string
+		want          error
+		wantRetryable bool
+	}{
+		{"empty body", jsonURL, "  ", nil, false},
+		{"json list", jsonURL, `[{"id":1}]`, nil, false},
+		{"json object", autocompleteURL, `{"label":"cat"}`, nil, false},
+		{"xml posts document", xmlURL, `<?xml version="1.0" encoding="UTF-8"?><tags type="array"><tag id="1" name="cat"/></tags>`, nil, false},
+		{"empty xml document", xmlURL, `<posts count="0" offset="0"/>`, nil, false},
+		{"successful xml response", xmlURL, `<response success="true"/>`, nil, false},
+		{"plain text auth", jsonURL, `"Missing authentication. Go to api.rule34.xxx for more information"`, ErrAuthRequired, false},
+		{"plain text search limit", jsonURL, "Search error: tag limit exceeded", ErrSearchLimit, false},
+		{"plain text rate limit", jsonURL, "Too many requests, slow down", ErrRateLimited, true},
+		{"plain text unknown", jsonURL, "Something went wrong", ErrUnexpectedResponse, false},
+		{"xml reason", xmlURL, `<?xml version="1.0"?><response success="false" reason="Invalid api_key"/>`, ErrAuthRequired, false},
+		{"xml reason rate limit", xmlURL, `<response success="false" reason="Rate limit exceeded"/>`, ErrRateLimited, true},
+		{"xml message", xmlURL, `<response success="false">search limit reached</response>`, ErrSearchLimit, false},
+		{"html on json endpoint", jsonURL, htmlPage, ErrUnexpectedResponse, false},
+		{"html on xml endpoint", xmlURL, htmlPage, ErrUnexpectedResponse, false},
+		{"xml on json endpoint", jsonURL, `<posts count="0" offset="0"/>`, ErrUnexpectedResponse, false},
+		{"xml on autocomplete", autocompleteURL, `<posts count="0" offset="0"/>`, ErrUnexpectedResponse, false},
+	}
+
+	for _, tt := range tests {
+		err := classifyResponse(tt.url, []byte(tt.body))
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("%s: error = %v, want an *APIError", tt.name, err)
+			continue
+		}
+
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want it to match %v", tt.name, err, tt.want)
+		}
+		if apiErr.Retryable != tt.wantRetryable {
+			t.Errorf("%s: retryable = %t, want %t", tt.name, apiErr.Retryable, tt.wantRetryable)
+		}
+		if apiErr.Endpoint == "" || apiErr.Snippet == "" {
+			t.Errorf("%s: endpoint %q and snippet %q must be set", tt.name, apiErr.Endpoint, apiErr.Snippet)
+		}
+	}
+}
+
+func TestAuthRequiredMatchesUnauthorized(t *testing.T) {
+	err := classifyResponse("https://api.rule34.xxx/index.php?page=dapi&s=post&q=index&json=1", []byte("Missing authentication"))
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("error = %v, want it to match ErrUnauthorized", err)
+	}
+}
@@ -2,32 +2,62 @@
 package rule34
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"time"
 )
 
 // Client represents a client for the rule34.xxx API.
 // It holds user credentials and an HTTP client to perform requests.
+// If a credential provider is set, it takes precedence over the UserID and APIKey fields.
 type Client struct {
-	UserID     string
-	APIKey     string
-	baseURL    string
-	httpClient *http.Client
+	UserID             string
+	APIKey             string
+	baseURL            string
+	httpClient         *http.Client
+	timeout            time.Duration
+	userAgent          string
+	retryPolicy        RetryPolicy
+	rateLimiter        *RateLimiter
+	credentialProvider CredentialProvider
+	configErrors       []error
 }
 
 // New creates a new instance of the rule34 client.
-// It requires a user ID and an API key for authentication.
-func New(id string, apiKey string) *Client {
-	return &Client{
-		UserID:  id,
-		APIKey:  apiKey,
-		baseURL: "https://api.rule34.xxx/index.php?page=dapi&q=index",
-		httpClient: &http.Client{
-			Timeout: time.Second * 5,
-		},
+// It requires a user ID and an API key for authentication,
+// and accepts options to customize the HTTP client, base URL, timeout, etc.
+func New(id string, apiKey string, opts ...Option) *Client {
+	c := &Client{
+		UserID:      id,
+		APIKey:      apiKey,
+		baseURL:     defaultBaseURL,
+		httpClient:  &http.Client{},
+		timeout:     defaultTimeout,
+		retryPolicy: NoRetryPolicy,
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
+}
+
+// NewAnonymous creates a new instance of the rule34 client without credentials.
+// Requests are sent without the user_id and api_key parameters, so endpoints
+// requiring authentication fail with an error matching ErrAnonymous and ErrUnauthorized.
+func NewAnonymous(opts ...Option) *Client {
+	return New("", "", opts...)
+}
+
+// IsAnonymous reports whether the client has neither credentials nor a credential provider.
+func (c *Client) IsAnonymous() bool {
+	return c.credentialProvider == nil && c.UserID == "" && c.APIKey == ""
 }
 
 // Posts returns a PostsRequestBuilder for building a request to fetch posts.
@@ -42,30 +72,210 @@ func (c *Client) Posts() *PostsRequestBuilder {
 	}
 }
 
-// Comments is intended to return a CommentRequestBuilder for fetching comments.
-// TODO: implement
-func (c *Client) Comments() {}
+// DeletedPosts returns a DeletedPostsRequestBuilder for building a request to fetch deleted posts.
+// This builder allows for paging through the deleted posts feed by the last seen post ID.
+func (c *Client) DeletedPosts() *DeletedPostsRequestBuilder {
+	return &DeletedPostsRequestBuilder{
+		client: c,
+	}
+}
+
+// Comments returns a CommentsRequestBuilder for building a request to fetch comments.
+// This builder allows for chaining methods to specify query parameters like the post ID.
+func (c *Client) Comments() *CommentsRequestBuilder {
+	return &CommentsRequestBuilder{
+		client: c,
+	}
+}
+
+// Tags returns a TagsRequestBuilder for building a request to fetch tags.
+// This builder allows for chaining methods to specify query parameters like names, IDs, ordering, etc.
+func (c *Client) Tags() *TagsRequestBuilder {
+	return &TagsRequestBuilder{
+		options: TagsOptions{
+			Names: make([]string, 0),
+		},
+		client: c,
+	}
+}
+
+// resolveCredentials returns the credentials for the next request attempt,
+// either from the credential provider or from the UserID and APIKey fields.
+func (c *Client) resolveCredentials(ctx context.Context) (Credentials, error) {
+	if c.credentialProvider == nil {
+		return Credentials{UserID: c.UserID, APIKey: c.APIKey}, nil
+	}
 
-// Tags is intended to return a TagRequestBuilder for fetching tags.
-// TODO: implement
-func (c *Client) Tags() {}
+	creds, err := c.credentialProvider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("can't resolve credentials: %w", err)
+	}
+
+	return creds, nil
+}
 
-// doRequest performs an HTTP GET request to the specified URL. It returns an
-// error for non-200 status codes, network issues, or problems reading the response body.
-func (c *Client) doRequest(url string) ([]byte, error) {
-	resp, err := c.httpClient.Get(url)
+// reportCredentials passes the outcome of a request attempt to the credential provider,
+// if it wants to know about it.
+func (c *Client) reportCredentials(creds Credentials, err error) {
+	if reporter, ok := c.credentialProvider.(CredentialReporter); ok {
+		reporter.Report(creds, err)
+	}
+}
+
+// canRotateCredentials reports whether an attempt rejected with the given error should be
+// repeated right away with other credentials. That is the case for authentication failures
+// when the credential provider is told about them, so it can stop handing out the rejected ones.
+func (c *Client) canRotateCredentials(err error) bool {
+	_, ok := c.credentialProvider.(CredentialReporter)
+	return ok && errors.Is(err, ErrUnauthorized)
+}
+
+// withCredentials adds the credentials as query parameters to the request URL.
+// Empty credentials are left out, so anonymous clients send none.
+func withCredentials(rawURL string, creds Credentials) (string, error) {
+	u, err := url.Parse(rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("can't do request: %v", err)
+		return "", fmt.Errorf("can't parse request URL: %w", err)
+	}
+
+	q := u.Query()
+	if creds.UserID != "" {
+		q.Set("user_id", creds.UserID)
 	}
 
+	if creds.APIKey != "" {
+		q.Set("api_key", creds.APIKey)
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// doRequest performs an HTTP GET request to the specified URL, bound to the given context.
+// The credentials are resolved and added to the URL before every attempt.
+// Failed attempts are retried according to the client's retry policy. Attempts rejected because
+// of their credentials are repeated right away with the next credentials if the provider is
+// a CredentialReporter, until it hands out credentials that were already rejected. It returns
+// an *APIError for non-200 status codes and for error payloads returned with HTTP 200, or an error
+// for network issues or problems reading the response body.
+func (c *Client) doRequest(ctx context.Context, url string) ([]byte, error) {
+	if len(c.configErrors) != 0 {
+		return nil, fmt.Errorf("invalid client options: %w", errors.Join(c.configErrors...))
+	}
+
+	start := time.Now()
+	attempts := 1
+
+	var rejected []Credentials
+	var authErr error
+
+	for {
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("can't wait for rate limiter: %w", err)
+			}
+		}
+
+		creds, err := c.resolveCredentials(ctx)
+		if err != nil {
+			if authErr != nil {
+				return nil, errors.Join(authErr, err)
+			}
+			return nil, err
+		}
+
+		if slices.Contains(rejected, creds) {
+			return nil, authErr
+		}
+
+		requestURL, err := withCredentials(url, creds)
+		if err != nil {
+			return nil, err
+		}
+
+		body, retryable, err := c.doAttempt(ctx, requestURL)
+		c.reportCredentials(creds, err)
+		if err == nil {
+			return body, nil
+		}
+
+		if c.IsAnonymous() && errors.Is(err, ErrUnauthorized) {
+			return nil, fmt.Errorf("%w: %w", ErrAnonymous, err)
+		}
+
+		if c.canRotateCredentials(err) {
+			rejected = append(rejected, creds)
+			authErr = err
+			continue
+		}
+
+		if !retryable || !c.retryPolicy.canRetry(attempts) {
+			return nil, err
+		}
+
+		var retryAfter time.Duration
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			retryAfter = apiErr.RetryAfter
+		}
+
+		delay := c.retryPolicy.backoff(attempts, retryAfter)
+		if c.retryPolicy.MaxElapsed > 0 && time.Since(start)+delay > c.retryPolicy.MaxElapsed {
+			return nil, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("can't do request: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		attempts++
+	}
+}
+
+// doAttempt performs a single HTTP GET request, bounded by the client's per-request timeout.
+// Besides the body and the error it reports whether the failure is transient.
+func (c *Client) doAttempt(ctx context.Context, url string) ([]byte, bool, error) {
+	attemptCtx := ctx
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(attemptCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("can't create request: %w", redactError(err))
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, fmt.Errorf("can't do request: %w", redactError(err))
+	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxSnippetLength))
+		apiErr := newAPIError(url, resp, body)
+		return nil, apiErr.Retryable, apiErr
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("can't read body: %v", err)
+		return nil, ctx.Err() == nil, fmt.Errorf("can't read body: %w", err)
+	}
+
+	if err := classifyResponse(url, body); err != nil {
+		var apiErr *APIError
+		return nil, errors.As(err, &apiErr) && apiErr.Retryable, err
 	}
 
-	return body, nil
+	return body, false, nil
 }
@@ -0,0 +1,78 @@
+package rule34
+
+import (
+	"net/http"
+	"time"
+)
+
+// defaultBaseURL is the DAPI endpoint of rule34.xxx used when no base URL option is given.
+const defaultBaseURL = "https://api.rule34.xxx/index.php?page=dapi&q=index"
+
+// defaultTimeout is the per-request timeout used when no timeout option is given.
+const defaultTimeout = time.Second * 5
+
+// Option configures a Client created by New.
+type Option func(*Client)
+
+// WithHTTPClient makes the client perform requests with the given HTTP client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithTransport makes the client perform requests through the given round tripper,
+// for example to route them through a proxy.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+}
+
+// WithBaseURL replaces the DAPI endpoint URL, for example to point the client at a local test server.
+// The URL must include the page=dapi and q=index query parameters.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithTimeout sets the timeout of every single request attempt.
+// A non-positive timeout disables it, leaving only the context deadline.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests. See SetRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimit limits all requests of the client to rps requests per second with bursts of up to burst requests.
+// Invalid values don't panic; instead every request of the client fails with ErrNonPositiveRate or ErrNonPositiveBurst.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Client) {
+		limiter, err := NewRateLimiter(rps, burst)
+		if err != nil {
+			c.configErrors = append(c.configErrors, err)
+			return
+		}
+
+		c.rateLimiter = limiter
+	}
+}
@@ -0,0 +1,93 @@
+package rule34
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// testBaseURL returns a DAPI base URL pointing at the given test server.
+func testBaseURL(server *httptest.Server) string {
+	return server.URL + "/index.php?page=dapi&q=index"
+}
+
+func TestDoRequestRotatesRejectedPoolCredentials(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("api_key")
+		keys = append(keys, key)
+		if key != "good" {
+			w.Write([]byte(`"Missing authentication. Go to api.rule34.xxx for more information"`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	pool, err := NewCredentialPool(
+		Credentials{UserID: "1", APIKey: "bad"},
+		Credentials{UserID: "2", APIKey: "revoked"},
+		Credentials{UserID: "3", APIKey: "good"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := New("", "", WithBaseURL(testBaseURL(server)), WithCredentialProvider(pool))
+	if _, err := c.doRequest(context.Background(), testBaseURL(server)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"bad", "revoked", "good"}; !slices.Equal(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+}
+
+func TestDoRequestStopsRotatingWhenEveryCredentialIsRejected(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	pool, err := NewCredentialPool(
+		Credentials{UserID: "1", APIKey: "a"},
+		Credentials{UserID: "2", APIKey: "b"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := New("", "", WithBaseURL(testBaseURL(server)), WithCredentialProvider(pool))
+	_, err = c.doRequest(context.Background(), testBaseURL(server))
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("error = %v, want it to match ErrUnauthorized", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestDoRequestDoesNotRotateStaticCredentials(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	c := New("1", "key", WithBaseURL(testBaseURL(server)), WithRetryPolicy(DefaultRetryPolicy))
+	_, err := c.doRequest(context.Background(), testBaseURL(server))
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("error = %v, want it to match ErrUnauthorized", err)
+	}
+
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
@@ -0,0 +1,58 @@
+package rule34
+
+import "slices"
+
+// Clone returns a deep copy of the builder. The copy shares no slices with the original,
+// so both can be modified independently, also from different goroutines.
+func (b *PostsRequestBuilder) Clone() *PostsRequestBuilder {
+	clone := *b
+	clone.options = b.options.clone()
+	clone.errors = slices.Clone(b.errors)
+
+	return &clone
+}
+
+// clone returns a deep copy of the options.
+func (o PostsOptions) clone() PostsOptions {
+	o.Tags = slices.Clone(o.Tags)
+	o.BlackList = slices.Clone(o.BlackList)
+	o.TagPatterns = slices.Clone(o.TagPatterns)
+	o.FilteringConditions = slices.Clone(o.FilteringConditions)
+	o.RangeConditions = slices.Clone(o.RangeConditions)
+
+	if o.OrGroups != nil {
+		groups := make([][]string, len(o.OrGroups))
+		for i, group := range o.OrGroups {
+			groups[i] = slices.Clone(group)
+		}
+		o.OrGroups = groups
+	}
+
+	return o
+}
+
+// PostsTemplate is an immutable base query, such as a blacklist with a rating,
+// from which any number of independent builders can be derived.
+// Templates are created with PostsRequestBuilder.Template and are safe for concurrent use by multiple goroutines.
+type PostsTemplate struct {
+	builder *PostsRequestBuilder
+}
+
+// Template freezes a copy of the builder into a reusable base query.
+// Later changes to the builder don't affect the template.
+func (b *PostsRequestBuilder) Template() PostsTemplate {
+	return PostsTemplate{
+		builder: b.Clone(),
+	}
+}
+
+// New returns a fresh builder initialized with the template's query and errors.
+// The builder can be extended and executed without affecting the template or other derived builders.
+func (t PostsTemplate) New() *PostsRequestBuilder {
+	return t.builder.Clone()
+}
+
+// Explain returns a breakdown of the template's query. See PostsRequestBuilder.Explain.
+func (t PostsTemplate) Explain() Explanation {
+	return t.builder.Clone().Explain()
+}
@@ -0,0 +1,104 @@
+package rule34
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// CommentsRequestBuilder is a builder for creating and executing API requests for comments.
+type CommentsRequestBuilder struct {
+	options CommentsOptions
+	client  *Client
+	errors  []error
+}
+
+// CommentsOptions holds all the configurable parameters for a comments API request.
+type CommentsOptions struct {
+	PostID int
+}
+
+// PostID restricts the request to the comments of the given post.
+func (b *CommentsRequestBuilder) PostID(postID int) *CommentsRequestBuilder {
+	if postID <= 0 {
+		b.errors = append(b.errors, ErrNonPositivePostID)
+		return b
+	}
+
+	b.options.PostID = postID
+	return b
+}
+
+// Find executes the request to the API with a background context. See FindContext.
+func (b *CommentsRequestBuilder) Find() (Comments, error) {
+	return b.FindContext(context.Background())
+}
+
+// FindContext executes the request to the API and returns the found comments.
+// It first validates any accumulated errors, then builds the URL, performs the request, and unmarshals the response.
+func (b *CommentsRequestBuilder) FindContext(ctx context.Context) (Comments, error) {
+	if len(b.errors) != 0 {
+		err := errors.Join(b.errors...)
+		return Comments{}, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	url, err := b.buildURL()
+	if err != nil {
+		return Comments{}, fmt.Errorf("failed to build url: %w", err)
+	}
+
+	commentsBytes, err := b.client.doRequest(ctx, url)
+	if err != nil {
+		return Comments{}, fmt.Errorf("failed to do get comments request: %w", err)
+	}
+
+	comments, err := unmarshalComments(commentsBytes)
+	if err != nil {
+		return Comments{}, fmt.Errorf("failed to unmarshal comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// buildURL constructs the final request URL from the builder's options.
+func (b *CommentsRequestBuilder) buildURL() (string, error) {
+	u, err := url.Parse(b.client.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("can't parse base URL: %w", err)
+	}
+
+	q := u.Query()
+	b.addOptions(&q)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// addOptions adds all configured options as query parameters to the URL.
+func (b *CommentsRequestBuilder) addOptions(q *url.Values) {
+	q.Set("s", "comment")
+
+	postID := strconv.Itoa(b.options.PostID)
+	if postID != "0" {
+		q.Set("post_id", postID)
+	}
+}
+
+// unmarshalComments parses the XML response body into the Comments model.
+func unmarshalComments(commentsBytes []byte) (Comments, error) {
+	if len(commentsBytes) == 0 {
+		return Comments{}, nil
+	}
+
+	var comments Comments
+
+	err := xml.Unmarshal(commentsBytes, &comments)
+	if err != nil {
+		return Comments{}, fmt.Errorf("failed to unmarshal comments: %w", err)
+	}
+
+	return comments, nil
+}
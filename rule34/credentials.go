@@ -0,0 +1,239 @@
+package rule34
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Pre-defined errors for credential providers.
+var (
+	// ErrMissingCredentials is returned when a provider can't find any credentials.
+	ErrMissingCredentials = errors.New("credentials are missing")
+	// ErrNoAvailableCredentials is returned when every credentials of a pool are disabled or cooling down.
+	ErrNoAvailableCredentials = errors.New("no available credentials in pool")
+)
+
+// Default environment variables read by EnvCredentials.
+const (
+	DefaultUserIDEnv = "RULE34_USER_ID"
+	DefaultAPIKeyEnv = "RULE34_API_KEY"
+)
+
+// defaultPoolCooldown is how long a rate-limited key is skipped when the API doesn't send Retry-After.
+const defaultPoolCooldown = time.Minute
+
+// Credentials holds a user ID and an API key for authentication.
+// It implements CredentialProvider by always returning itself.
+type Credentials struct {
+	UserID string `json:"user_id"`
+	APIKey string `json:"api_key"`
+}
+
+// CredentialProvider resolves the credentials to use for a request.
+// It is called before every request attempt, so it may return different credentials over time.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialReporter is implemented by credential providers that want to know the outcome
+// of the requests made with the credentials they returned. The error is nil on success.
+type CredentialReporter interface {
+	Report(creds Credentials, err error)
+}
+
+// WithCredentialProvider makes the client resolve credentials from the given provider before every request,
+// instead of using the UserID and APIKey fields.
+func WithCredentialProvider(provider CredentialProvider) Option {
+	return func(c *Client) {
+		c.credentialProvider = provider
+	}
+}
+
+// Credentials implements the CredentialProvider interface.
+func (c Credentials) Credentials(context.Context) (Credentials, error) {
+	return c, nil
+}
+
+// IsEmpty reports whether neither the user ID nor the API key is set.
+func (c Credentials) IsEmpty() bool {
+	return c.UserID == "" && c.APIKey == ""
+}
+
+// EnvCredentials is a CredentialProvider reading the credentials from environment variables on every request.
+type EnvCredentials struct {
+	UserIDEnv string
+	APIKeyEnv string
+}
+
+// NewEnvCredentials creates a provider reading the RULE34_USER_ID and RULE34_API_KEY environment variables.
+func NewEnvCredentials() EnvCredentials {
+	return EnvCredentials{
+		UserIDEnv: DefaultUserIDEnv,
+		APIKeyEnv: DefaultAPIKeyEnv,
+	}
+}
+
+// Credentials implements the CredentialProvider interface.
+func (e EnvCredentials) Credentials(context.Context) (Credentials, error) {
+	creds := Credentials{
+		UserID: os.Getenv(e.UserIDEnv),
+		APIKey: os.Getenv(e.APIKeyEnv),
+	}
+
+	if creds.UserID == "" || creds.APIKey == "" {
+		return Credentials{}, fmt.Errorf("%w: set %s and %s", ErrMissingCredentials, e.UserIDEnv, e.APIKeyEnv)
+	}
+
+	return creds, nil
+}
+
+// FileCredentials is a CredentialProvider reading the credentials from a JSON file
+// of the form {"user_id": "...", "api_key": "..."}. The file is read again whenever it changes,
+// so keys can be rotated without restarting.
+type FileCredentials struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	creds   Credentials
+}
+
+// NewFileCredentials creates a provider reading the credentials from the JSON file at the given path.
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{
+		path: path,
+	}
+}
+
+// Credentials implements the CredentialProvider interface.
+func (f *FileCredentials) Credentials(context.Context) (Credentials, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("can't stat credentials file: %w", err)
+	}
+
+	if info.ModTime().Equal(f.modTime) && !f.creds.IsEmpty() {
+		return f.creds, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("can't read credentials file: %w", err)
+	}
+
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return Credentials{}, fmt.Errorf("can't parse credentials file: %w", err)
+	}
+
+	if creds.UserID == "" || creds.APIKey == "" {
+		return Credentials{}, fmt.Errorf("%w: in %s", ErrMissingCredentials, f.path)
+	}
+
+	f.modTime = info.ModTime()
+	f.creds = creds
+	return creds, nil
+}
+
+// CredentialPool is a CredentialProvider spreading requests over several accounts in round-robin order.
+// Credentials rejected by the API are disabled and the request is repeated right away with the next
+// credentials, and rate-limited credentials are skipped until the delay requested by the API has passed.
+type CredentialPool struct {
+	mu      sync.Mutex
+	entries []poolEntry
+	next    int
+}
+
+// poolEntry holds the state of a single credentials in a pool.
+type poolEntry struct {
+	creds    Credentials
+	disabled bool
+	until    time.Time
+}
+
+// NewCredentialPool creates a pool of the given credentials.
+func NewCredentialPool(creds ...Credentials) (*CredentialPool, error) {
+	if len(creds) == 0 {
+		return nil, ErrMissingCredentials
+	}
+
+	entries := make([]poolEntry, 0, len(creds))
+	for _, c := range creds {
+		if c.UserID == "" || c.APIKey == "" {
+			return nil, ErrMissingCredentials
+		}
+		entries = append(entries, poolEntry{creds: c})
+	}
+
+	return &CredentialPool{
+		entries: entries,
+	}, nil
+}
+
+// Credentials implements the CredentialProvider interface.
+func (p *CredentialPool) Credentials(context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for range p.entries {
+		entry := &p.entries[p.next]
+		p.next = (p.next + 1) % len(p.entries)
+
+		if entry.disabled || now.Before(entry.until) {
+			continue
+		}
+
+		return entry.creds, nil
+	}
+
+	return Credentials{}, ErrNoAvailableCredentials
+}
+
+// Report implements the CredentialReporter interface.
+// It disables credentials rejected by the API and puts rate-limited credentials on cooldown.
+func (p *CredentialPool) Report(creds Credentials, err error) {
+	if err == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := range p.entries {
+		entry := &p.entries[i]
+		if entry.creds != creds {
+			continue
+		}
+
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			entry.disabled = true
+		case errors.Is(err, ErrRateLimited):
+			cooldown := defaultPoolCooldown
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+				cooldown = apiErr.RetryAfter
+			}
+			entry.until = time.Now().Add(cooldown)
+		}
+	}
+}
+
+// Reset re-enables all credentials of the pool and clears their cooldowns.
+func (p *CredentialPool) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := range p.entries {
+		p.entries[i].disabled = false
+		p.entries[i].until = time.Time{}
+	}
+}
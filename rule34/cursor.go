@@ -0,0 +1,129 @@
+package rule34
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// Pre-defined errors for cursor-based paging.
+var (
+	// ErrInvalidCursor is returned when a cursor token can't be decoded.
+	ErrInvalidCursor = errors.New("invalid cursor was given")
+	// ErrCursorWithPageNumber is returned when cursor-based paging is combined with a page number.
+	ErrCursorWithPageNumber = errors.New("cursor paging can't be combined with page number")
+	// ErrCursorWithSorting is returned when cursor-based paging is combined with a sorting other than by ID descending.
+	ErrCursorWithSorting = errors.New("cursor paging requires sorting by id in descending order")
+)
+
+// cursorPrefix is prepended to the post ID before encoding it into a cursor token.
+const cursorPrefix = "id:"
+
+// Cursor is an opaque, resumable position in a keyset-paginated search.
+// It points just past a given post, so a search started from it continues with older posts.
+type Cursor string
+
+// Cursor returns the token that resumes a keyset-paginated search right after this post.
+func (p Post) Cursor() Cursor {
+	return newCursor(p.ID)
+}
+
+// newCursor encodes the post ID into a cursor token.
+func newCursor(postID int) Cursor {
+	token := base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(postID)))
+	return Cursor(token)
+}
+
+// postID decodes the post ID from the cursor token.
+func (c Cursor) postID() (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	idStr, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return id, nil
+}
+
+// After restricts the request to posts older than the one the cursor points at.
+// It is the starting point for AllByCursor and can also be used with Find.
+func (b *PostsRequestBuilder) After(cursor Cursor) *PostsRequestBuilder {
+	postID, err := cursor.postID()
+	if err != nil {
+		b.errors = append(b.errors, err)
+		return b
+	}
+
+	b.options.CursorPostID = postID
+	return b
+}
+
+// AllByCursor returns an iterator over every post matching the request using keyset pagination.
+// Instead of page numbers it walks the results by descending post ID with an id:< condition,
+// so it is not limited by the page depth of the API and doesn't skip or repeat posts that shift
+// while crawling. Every yielded post's Cursor can be passed to After to resume the walk later.
+// An error is yielded once together with a zero Post, after which the iteration ends.
+func (b *PostsRequestBuilder) AllByCursor(ctx context.Context) iter.Seq2[Post, error] {
+	return func(yield func(Post, error) bool) {
+		if b.options.PageNumber != 0 {
+			yield(Post{}, fmt.Errorf("invalid arguments: %w", ErrCursorWithPageNumber))
+			return
+		}
+
+		if b.options.DoSort && (b.options.SortableType != SortByID || b.options.SortingOrder == "asc") {
+			yield(Post{}, fmt.Errorf("invalid arguments: %w", ErrCursorWithSorting))
+			return
+		}
+
+		pageLimit := b.pageLimit()
+
+		page := b.Clone()
+		page.options.DoSort = true
+		page.options.SortableType = SortByID
+		page.options.SortingOrder = "desc"
+
+		yielded := 0
+		for {
+			page.options.Limit = pageLimit
+			if remaining := b.remainingResults(yielded); remaining > 0 {
+				page.options.Limit = min(pageLimit, remaining)
+			}
+
+			posts, err := page.FindContext(ctx)
+			if err != nil {
+				yield(Post{}, err)
+				return
+			}
+
+			for _, post := range posts {
+				if !yield(post, nil) {
+					return
+				}
+
+				yielded++
+				if b.options.MaxResults != 0 && yielded >= b.options.MaxResults {
+					return
+				}
+			}
+
+			if len(posts) < page.options.Limit {
+				return
+			}
+
+			page.options.CursorPostID = posts[len(posts)-1].ID
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package rule34
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Pre-defined errors for the DeletedPostsRequestBuilder.
+var (
+	// ErrNegativeLastID is returned when a negative last ID is provided.
+	ErrNegativeLastID = errors.New("last id can't be less than zero")
+)
+
+// DeletedPostsRequestBuilder is a builder for creating and executing API requests for deleted posts.
+type DeletedPostsRequestBuilder struct {
+	options DeletedPostsOptions
+	client  *Client
+	errors  []error
+}
+
+// DeletedPostsOptions holds all the configurable parameters for a deleted posts API request.
+type DeletedPostsOptions struct {
+	LastID int
+}
+
+// LastID restricts the request to posts deleted with an ID above the given one.
+// Pass the LastID of the previous batch to page through the feed incrementally.
+func (b *DeletedPostsRequestBuilder) LastID(lastID int) *DeletedPostsRequestBuilder {
+	if lastID < 0 {
+		b.errors = append(b.errors, ErrNegativeLastID)
+		return b
+	}
+
+	b.options.LastID = lastID
+	return b
+}
+
+// Find executes the request to the API with a background context. See FindContext.
+func (b *DeletedPostsRequestBuilder) Find() (DeletedPosts, error) {
+	return b.FindContext(context.Background())
+}
+
+// FindContext executes the request to the API and returns the found deleted posts.
+// It first validates any accumulated errors, then builds the URL, performs the request, and unmarshals the response.
+func (b *DeletedPostsRequestBuilder) FindContext(ctx context.Context) (DeletedPosts, error) {
+	if len(b.errors) != 0 {
+		err := errors.Join(b.errors...)
+		return DeletedPosts{}, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	url, err := b.buildURL()
+	if err != nil {
+		return DeletedPosts{}, fmt.Errorf("failed to build url: %w", err)
+	}
+
+	deletedBytes, err := b.client.doRequest(ctx, url)
+	if err != nil {
+		return DeletedPosts{}, fmt.Errorf("failed to do get deleted posts request: %w", err)
+	}
+
+	deleted, err := unmarshalDeletedPosts(deletedBytes)
+	if err != nil {
+		return DeletedPosts{}, fmt.Errorf("failed to unmarshal deleted posts: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// buildURL constructs the final request URL from the builder's options.
+func (b *DeletedPostsRequestBuilder) buildURL() (string, error) {
+	u, err := url.Parse(b.client.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("can't parse base URL: %w", err)
+	}
+
+	q := u.Query()
+	b.addOptions(&q)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// addOptions adds all configured options as query parameters to the URL.
+func (b *DeletedPostsRequestBuilder) addOptions(q *url.Values) {
+	q.Set("s", "post")
+	q.Set("deleted", "show")
+
+	lastID := strconv.Itoa(b.options.LastID)
+	if lastID != "0" {
+		q.Set("last_id", lastID)
+	}
+}
+
+// unmarshalDeletedPosts parses the XML response body into the DeletedPosts model.
+func unmarshalDeletedPosts(deletedBytes []byte) (DeletedPosts, error) {
+	if len(deletedBytes) == 0 {
+		return DeletedPosts{}, nil
+	}
+
+	var deleted DeletedPosts
+
+	err := xml.Unmarshal(deletedBytes, &deleted)
+	if err != nil {
+		return DeletedPosts{}, fmt.Errorf("failed to unmarshal deleted posts: %w", err)
+	}
+
+	return deleted, nil
+}
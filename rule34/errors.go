@@ -0,0 +1,108 @@
+package rule34
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+)
+
+// Pre-defined errors describing the kind of an API failure.
+// They can be matched with errors.Is against any error returned by the client.
+var (
+	// ErrUnauthorized is returned when the API rejects the credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited is returned when the API throttles the client.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNotFound is returned when the requested resource doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAnonymous is returned when an anonymous client calls an endpoint that requires authentication.
+	ErrAnonymous = errors.New("endpoint requires credentials but the client is anonymous")
+)
+
+// maxSnippetLength is the maximum number of response body bytes kept in an APIError.
+const maxSnippetLength = 512
+
+// APIError describes a failed API request. It can be extracted with errors.As
+// to inspect the status code, endpoint and the beginning of the response body.
+type APIError struct {
+	StatusCode int           // HTTP status code of the response
+	Endpoint   string        // Requested endpoint, for example "index.php?s=post"
+	Snippet    string        // Beginning of the response body
+	Retryable  bool          // Whether repeating the request may succeed
+	RetryAfter time.Duration // Delay requested by the server via Retry-After, if any
+	Err        error         // Sentinel error describing the failure kind, if known
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("unexpected status %d %s from %s", e.StatusCode, http.StatusText(e.StatusCode), e.Endpoint)
+	if e.StatusCode == http.StatusOK {
+		msg = fmt.Sprintf("error response from %s: %q", e.Endpoint, e.Snippet)
+	}
+
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+
+	return msg
+}
+
+// Unwrap returns the sentinel error describing the failure kind, so errors.Is
+// matches ErrUnauthorized, ErrRateLimited or ErrNotFound.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError creates an APIError for a non-200 response of the given request URL.
+func newAPIError(requestURL string, resp *http.Response, body []byte) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Endpoint:   endpointOf(requestURL),
+		Snippet:    snippetOf(body),
+		Retryable:  isRetryableStatus(resp.StatusCode),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		Err:        sentinelForStatus(resp.StatusCode),
+	}
+}
+
+// sentinelForStatus maps an HTTP status code to the sentinel error of its failure kind.
+func sentinelForStatus(code int) error {
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusNotFound:
+		return ErrNotFound
+	default:
+		return nil
+	}
+}
+
+// endpointOf returns the endpoint part of a request URL without any parameters but the resource type,
+// so it never contains credentials.
+func endpointOf(requestURL string) string {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "unknown endpoint"
+	}
+
+	endpoint := path.Base(u.Path)
+	if s := u.Query().Get("s"); s != "" {
+		endpoint = fmt.Sprintf("%s?s=%s", endpoint, s)
+	}
+
+	return endpoint
+}
+
+// snippetOf returns the beginning of the response body, truncated to maxSnippetLength bytes.
+func snippetOf(body []byte) string {
+	if len(body) > maxSnippetLength {
+		body = body[:maxSnippetLength]
+	}
+
+	return string(body)
+}
@@ -0,0 +1,164 @@
+package rule34
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ClauseKind describes what a clause of the tag string sent to the API does.
+type ClauseKind string
+
+// Defines the kinds of clauses a posts request can consist of.
+const (
+	ClauseTag       ClauseKind = "tag"
+	ClausePattern   ClauseKind = "pattern"
+	ClauseOrGroup   ClauseKind = "or-group"
+	ClauseExclusion ClauseKind = "exclusion"
+	ClauseMeta      ClauseKind = "meta"
+	ClauseSort      ClauseKind = "sort"
+)
+
+// Clause is a single space-separated part of the tag string sent to the API,
+// together with the builder call it comes from.
+type Clause struct {
+	Kind   ClauseKind
+	Value  string // The clause as sent to the API, for example "score:>=50"
+	Source string // The builder call producing the clause, for example "Where"
+}
+
+// Explanation is a structured breakdown of a posts request. See PostsRequestBuilder.Explain.
+type Explanation struct {
+	Clauses   []Clause
+	TagString string  // The complete tag string sent to the API
+	URL       string  // The request URL with credentials masked
+	Errors    []error // Errors accumulated by the builder, which would make Find fail
+}
+
+// String implements the fmt.Stringer interface by listing the clauses, the URL and the errors, one per line.
+func (e Explanation) String() string {
+	sb := strings.Builder{}
+
+	for _, clause := range e.Clauses {
+		sb.WriteString(fmt.Sprintf("%-9s %-30s from %s\n", clause.Kind, clause.Value, clause.Source))
+	}
+
+	sb.WriteString(fmt.Sprintf("url: %s\n", e.URL))
+
+	for _, err := range e.Errors {
+		sb.WriteString(fmt.Sprintf("error: %v\n", err))
+	}
+
+	return sb.String()
+}
+
+// Explain returns a breakdown of what the request would send to the API without making a network call.
+// It lists every clause of the tag string with the builder call it comes from, the redacted URL,
+// and the errors accumulated by the builder so far.
+func (b *PostsRequestBuilder) Explain() Explanation {
+	explanation := Explanation{
+		Clauses:   b.clauses(),
+		TagString: strings.TrimSpace(b.convertTags()),
+		Errors:    append([]error(nil), b.errors...),
+	}
+
+	u, err := b.RedactedURL()
+	if err != nil {
+		explanation.Errors = append(explanation.Errors, fmt.Errorf("failed to build url: %w", err))
+	} else {
+		explanation.URL = u
+	}
+
+	return explanation
+}
+
+// clauses lists all tags, blacklisted tags, and meta-tags of the request in the order they are sent.
+func (b *PostsRequestBuilder) clauses() []Clause {
+	var clauses []Clause
+
+	for _, tag := range b.options.Tags {
+		clauses = append(clauses, Clause{Kind: ClauseTag, Value: tag, Source: "Tags"})
+	}
+
+	for _, tag := range b.options.TagPatterns {
+		clauses = append(clauses, Clause{Kind: ClausePattern, Value: tag, Source: "TagPattern"})
+	}
+
+	for _, group := range b.options.OrGroups {
+		value := fmt.Sprintf("( %s )", strings.Join(group, " ~ "))
+		clauses = append(clauses, Clause{Kind: ClauseOrGroup, Value: value, Source: "AnyOf"})
+	}
+
+	for _, tag := range b.options.BlackList {
+		clauses = append(clauses, Clause{Kind: ClauseExclusion, Value: "-" + tag, Source: "BlackList"})
+	}
+
+	if b.options.FilterAI {
+		clauses = append(clauses, Clause{Kind: ClauseExclusion, Value: "-" + aiGeneratedTag, Source: "FilterAI"})
+	}
+
+	if b.options.Rating != "" {
+		value := fmt.Sprintf("rating:%s", b.options.Rating)
+		clauses = append(clauses, Clause{Kind: ClauseMeta, Value: value, Source: "Rating"})
+	}
+
+	if b.options.ParentPostID != 0 {
+		value := fmt.Sprintf("parent:%d", b.options.ParentPostID)
+		clauses = append(clauses, Clause{Kind: ClauseMeta, Value: value, Source: "ParentPostID"})
+	}
+
+	for _, fc := range b.options.FilteringConditions {
+		value := fmt.Sprintf("%s:%s%d", fc.FilteringType, fc.Operation, fc.Argument)
+		clauses = append(clauses, Clause{Kind: ClauseMeta, Value: value, Source: "Where"})
+	}
+
+	for _, rc := range b.options.RangeConditions {
+		value := fmt.Sprintf("%s:%d..%d", rc.FilteringType, rc.From, rc.To)
+		clauses = append(clauses, Clause{Kind: ClauseMeta, Value: value, Source: "WhereBetween"})
+	}
+
+	if b.options.MD5 != "" {
+		value := fmt.Sprintf("md5:%s", b.options.MD5)
+		clauses = append(clauses, Clause{Kind: ClauseMeta, Value: value, Source: "MD5"})
+	}
+
+	if b.options.Source != "" {
+		value := fmt.Sprintf("source:%s", b.options.Source)
+		clauses = append(clauses, Clause{Kind: ClauseMeta, Value: value, Source: "Source"})
+	}
+
+	if b.options.User != "" {
+		value := fmt.Sprintf("user:%s", b.options.User)
+		clauses = append(clauses, Clause{Kind: ClauseMeta, Value: value, Source: "User"})
+	}
+
+	if b.options.Status != "" {
+		value := fmt.Sprintf("status:%s", b.options.Status)
+		clauses = append(clauses, Clause{Kind: ClauseMeta, Value: value, Source: "Status"})
+	}
+
+	if b.options.CursorPostID != 0 {
+		value := fmt.Sprintf("id:<%d", b.options.CursorPostID)
+		clauses = append(clauses, Clause{Kind: ClauseMeta, Value: value, Source: "After"})
+	}
+
+	if b.options.DoSort {
+		order := b.options.SortingOrder
+		source := "SortBy"
+		switch order {
+		case "":
+			order = "desc"
+		case "asc":
+			source = "SortBy+Asc"
+		case "desc":
+			source = "SortBy+Desc"
+		}
+
+		value := fmt.Sprintf("sort:%s:%s", b.options.SortableType, order)
+		if b.options.SortableType == SortByRandom {
+			value = fmt.Sprintf("sort:%s", SortByRandom)
+		}
+		clauses = append(clauses, Clause{Kind: ClauseSort, Value: value, Source: source})
+	}
+
+	return clauses
+}
@@ -0,0 +1,112 @@
+package rule34
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// Pre-defined errors for iterating over posts.
+var (
+	// ErrNonPositiveMaxResults is returned when a non-positive max results cap is provided.
+	ErrNonPositiveMaxResults = errors.New("max results can't be less than or equal to zero")
+)
+
+// defaultPageLimit is the number of posts the API returns per page when no limit is set.
+const defaultPageLimit = 100
+
+// maxPageLimit is the hard limit of posts the API returns per page.
+const maxPageLimit = 1000
+
+// MaxResults caps the total number of posts yielded by All.
+func (b *PostsRequestBuilder) MaxResults(maxResults int) *PostsRequestBuilder {
+	if maxResults <= 0 {
+		b.errors = append(b.errors, ErrNonPositiveMaxResults)
+		return b
+	}
+
+	b.options.MaxResults = maxResults
+	return b
+}
+
+// All returns an iterator over every post matching the request.
+// Pages are fetched lazily starting from the configured page number, and iteration stops
+// when a short page comes back, the MaxResults cap is reached, or an error occurs.
+// Near the MaxResults cap smaller pages are requested, so no more posts are fetched than needed
+// where the page grid of the API allows it.
+// An error is yielded once together with a zero Post, after which the iteration ends.
+func (b *PostsRequestBuilder) All(ctx context.Context) iter.Seq2[Post, error] {
+	return func(yield func(Post, error) bool) {
+		pageLimit := b.pageLimit()
+
+		page := b.Clone()
+		offset := b.options.PageNumber * pageLimit
+
+		yielded := 0
+		for {
+			page.options.PageNumber, page.options.Limit = pageAt(offset, pageLimit, b.remainingResults(yielded))
+
+			posts, err := page.FindContext(ctx)
+			if err != nil {
+				yield(Post{}, err)
+				return
+			}
+
+			for _, post := range posts {
+				if !yield(post, nil) {
+					return
+				}
+
+				yielded++
+				if b.options.MaxResults != 0 && yielded >= b.options.MaxResults {
+					return
+				}
+			}
+
+			if len(posts) < page.options.Limit {
+				return
+			}
+
+			offset += len(posts)
+		}
+	}
+}
+
+// pageLimit returns the number of posts to request per page while iterating.
+// It defaults to the API's page size and is clamped to the API's hard limit,
+// so a full page is never mistaken for the last one.
+func (b *PostsRequestBuilder) pageLimit() int {
+	if b.options.Limit == 0 {
+		return defaultPageLimit
+	}
+
+	return min(b.options.Limit, maxPageLimit)
+}
+
+// remainingResults returns how many posts may still be yielded after the given number,
+// or zero if MaxResults isn't set.
+func (b *PostsRequestBuilder) remainingResults(yielded int) int {
+	if b.options.MaxResults == 0 {
+		return 0
+	}
+
+	return b.options.MaxResults - yielded
+}
+
+// pageAt returns the page number and the page size to request the posts starting at the given offset,
+// which is a multiple of pageLimit. Since the API computes the offset of a page as its number times
+// its size, a smaller page for the remaining posts must have a size dividing the offset, so the
+// smallest such size of at least remaining is used. A zero remaining means no cap.
+func pageAt(offset, pageLimit, remaining int) (int, int) {
+	limit := pageLimit
+	if remaining > 0 && remaining < pageLimit {
+		for size := remaining; size < pageLimit; size++ {
+			if offset%size == 0 {
+				limit = size
+				break
+			}
+		}
+	}
+
+	return offset / limit, limit
+}
@@ -0,0 +1,161 @@
+package rule34
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// pageRequest records the page number and size of a request to postsServer.
+type pageRequest struct {
+	pid   int
+	limit int
+}
+
+// postsServer serves total posts with descending IDs, paginated by pid and limit
+// or filtered by an id:< cursor tag like the API. It records every request.
+type postsServer struct {
+	mu       sync.Mutex
+	total    int
+	requests []pageRequest
+}
+
+func (s *postsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	pid, _ := strconv.Atoi(q.Get("pid"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, pageRequest{pid: pid, limit: limit})
+	s.mu.Unlock()
+
+	below := s.total + 1
+	for _, tag := range strings.Fields(q.Get("tags")) {
+		if id, ok := strings.CutPrefix(tag, "id:<"); ok {
+			below, _ = strconv.Atoi(id)
+		}
+	}
+
+	var ids []int
+	for id := s.total; id > 0; id-- {
+		if id < below {
+			ids = append(ids, id)
+		}
+	}
+
+	start := min(pid*limit, len(ids))
+	end := min(start+limit, len(ids))
+
+	posts := make([]map[string]int, 0, end-start)
+	for _, id := range ids[start:end] {
+		posts = append(posts, map[string]int{"id": id})
+	}
+
+	json.NewEncoder(w).Encode(posts)
+}
+
+// collectIDs runs the iterator and returns the yielded post IDs.
+func collectIDs(t *testing.T, seq func(yield func(Post, error) bool)) []int {
+	t.Helper()
+
+	var ids []int
+	for post, err := range seq {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, post.ID)
+	}
+
+	return ids
+}
+
+// descendingIDs returns n IDs counting down from the given one.
+func descendingIDs(from, n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = from - i
+	}
+
+	return ids
+}
+
+func TestAllRequestsNoMoreThanMaxResults(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        int
+		pageNumber   int
+		maxResults   int
+		wantRequests []pageRequest
+	}{
+		{"below default page size", 0, 0, 5, []pageRequest{{0, 5}}},
+		{"divisible remainder", 100, 0, 150, []pageRequest{{0, 100}, {2, 50}}},
+		{"rounded up remainder", 100, 0, 130, []pageRequest{{0, 100}, {2, 50}}},
+		{"from a later page", 100, 1, 7, []pageRequest{{10, 10}}},
+		{"multiple of page size", 100, 0, 200, []pageRequest{{0, 100}, {1, 100}}},
+		{"page size above the API limit", 5000, 0, 1500, []pageRequest{{0, 1000}, {2, 500}}},
+	}
+
+	for _, tt := range tests {
+		srv := &postsServer{total: 3000}
+		server := httptest.NewServer(srv)
+
+		b := New("1", "key", WithBaseURL(testBaseURL(server))).Posts().MaxResults(tt.maxResults)
+		if tt.limit != 0 {
+			b.Limit(tt.limit)
+		}
+		if tt.pageNumber != 0 {
+			b.PageNumber(tt.pageNumber)
+		}
+
+		ids := collectIDs(t, b.All(context.Background()))
+		server.Close()
+
+		offset := tt.pageNumber * b.pageLimit()
+		if want := descendingIDs(srv.total-offset, tt.maxResults); !slices.Equal(ids, want) {
+			t.Errorf("%s: yielded %d posts from %v, want %d from %d", tt.name, len(ids), ids[:min(len(ids), 1)], len(want), want[0])
+		}
+
+		if !slices.Equal(srv.requests, tt.wantRequests) {
+			t.Errorf("%s: requests = %v, want %v", tt.name, srv.requests, tt.wantRequests)
+		}
+	}
+}
+
+func TestAllStopsOnShortPage(t *testing.T) {
+	srv := &postsServer{total: 7}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	b := New("1", "key", WithBaseURL(testBaseURL(server))).Posts().Limit(5)
+	if ids := collectIDs(t, b.All(context.Background())); !slices.Equal(ids, descendingIDs(7, 7)) {
+		t.Errorf("ids = %v, want 7 down to 1", ids)
+	}
+
+	if want := []pageRequest{{0, 5}, {1, 5}}; !slices.Equal(srv.requests, want) {
+		t.Errorf("requests = %v, want %v", srv.requests, want)
+	}
+}
+
+func TestAllByCursorRequestsNoMoreThanMaxResults(t *testing.T) {
+	srv := &postsServer{total: 3000}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	b := New("1", "key", WithBaseURL(testBaseURL(server))).Posts().MaxResults(130)
+	if ids := collectIDs(t, b.AllByCursor(context.Background())); !slices.Equal(ids, descendingIDs(3000, 130)) {
+		t.Errorf("yielded %d posts, want 3000 down to 2871", len(ids))
+	}
+
+	if want := []pageRequest{{0, 100}, {0, 30}}; !slices.Equal(srv.requests, want) {
+		t.Errorf("requests = %v, want %v", srv.requests, want)
+	}
+}
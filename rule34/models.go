@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"encoding/json"
+	"strconv"
 	"strings"
 )
 
@@ -14,30 +15,40 @@ import (
 type Posts []Post
 
 // Post represents a single post object and its associated metadata.
-// The struct tags map the JSON keys from the API response to the fields.
+// The struct tags map the JSON keys and the XML attributes from the API response to the fields.
 type Post struct {
-	PreviewURL   string    `json:"preview_url"`
-	SampleURL    string    `json:"sample_url"`
-	FileURL      string    `json:"file_url"`
-	Directory    int       `json:"directory"`
-	Hash         string    `json:"hash"`
-	Width        int       `json:"width"`
-	Height       int       `json:"height"`
-	ID           int       `json:"id"`
-	Image        string    `json:"image"`
-	Change       int       `json:"change"`
-	Owner        string    `json:"owner"`
-	ParentID     int       `json:"parent_id"`
-	Rating       string    `json:"rating"`
-	Sample       bool      `json:"sample"`
-	SampleHeight int       `json:"sample_height"`
-	SampleWidth  int       `json:"sample_width"`
-	Score        int       `json:"score"`
-	Tags         TagsSlice `json:"tags"` // Custom type to handle space-separated string
-	Source       string    `json:"source"`
-	Status       string    `json:"status"`
-	HasNotes     bool      `json:"has_notes"`
-	CommentCount int       `json:"comment_count"`
+	PreviewURL   string    `json:"preview_url" xml:"preview_url,attr"`
+	SampleURL    string    `json:"sample_url" xml:"sample_url,attr"`
+	FileURL      string    `json:"file_url" xml:"file_url,attr"`
+	Directory    int       `json:"directory" xml:"-"`
+	Hash         string    `json:"hash" xml:"md5,attr"`
+	Width        int       `json:"width" xml:"width,attr"`
+	Height       int       `json:"height" xml:"height,attr"`
+	ID           int       `json:"id" xml:"id,attr"`
+	Image        string    `json:"image" xml:"-"`
+	Change       int       `json:"change" xml:"change,attr"`
+	Owner        string    `json:"owner" xml:"-"`
+	CreatorID    int       `json:"-" xml:"creator_id,attr"` // Uploader ID, only set by XML responses
+	ParentID     int       `json:"parent_id" xml:"parent_id,attr"`
+	Rating       string    `json:"rating" xml:"rating,attr"`
+	Sample       bool      `json:"sample" xml:"-"`
+	SampleHeight int       `json:"sample_height" xml:"sample_height,attr"`
+	SampleWidth  int       `json:"sample_width" xml:"sample_width,attr"`
+	Score        int       `json:"score" xml:"score,attr"`
+	Tags         TagsSlice `json:"tags" xml:"tags,attr"` // Custom type to handle space-separated string
+	Source       string    `json:"source" xml:"source,attr"`
+	Status       string    `json:"status" xml:"status,attr"`
+	HasNotes     bool      `json:"has_notes" xml:"has_notes,attr"`
+	CommentCount int       `json:"comment_count" xml:"-"`
+}
+
+// PostsPage is a single page of posts together with the paging metadata
+// that only the XML posts response carries.
+type PostsPage struct {
+	XMLName xml.Name `xml:"posts"`
+	Count   int      `xml:"count,attr"`  // Total number of posts matching the query
+	Offset  int      `xml:"offset,attr"` // Offset of the first post of this page
+	Posts   Posts    `xml:"post"`
 }
 
 // Comments represents the top-level structure for a comments API response,
@@ -130,9 +141,89 @@ func (cd *CreatedAt) UnmarshalXMLAttr(attr xml.Attr) error {
 
 	date, err := time.Parse(createdAtFormat, dateString)
 	if err != nil {
-		return fmt.Errorf("can't parse date: %v", err)
+		return fmt.Errorf("can't parse date: %w", err)
 	}
 
 	*cd = CreatedAt{date}
 	return nil
 }
+
+// Suggestions is a slice of Suggestion objects, representing the tag
+// completions returned by the autocomplete endpoint in JSON format.
+type Suggestions []Suggestion
+
+// Suggestion represents a single tag completion. The label has the form
+// "name (count)", so the post count is parsed out of it into Count.
+type Suggestion struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+	Count int    `json:"-"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It decodes the raw suggestion fields and then extracts the post count
+// from the trailing parenthesized number of the label, if there is one.
+func (s *Suggestion) UnmarshalJSON(data []byte) error {
+	type rawSuggestion Suggestion
+
+	var raw rawSuggestion
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*s = Suggestion(raw)
+	s.Count = parseSuggestionCount(s.Label)
+	return nil
+}
+
+// parseSuggestionCount returns the number enclosed in the trailing parentheses
+// of a suggestion label, or zero if the label has no such count.
+func parseSuggestionCount(label string) int {
+	label = strings.TrimSpace(label)
+	if !strings.HasSuffix(label, ")") {
+		return 0
+	}
+
+	open := strings.LastIndex(label, "(")
+	if open == -1 {
+		return 0
+	}
+
+	count, err := strconv.Atoi(label[open+1 : len(label)-1])
+	if err != nil {
+		return 0
+	}
+
+	return count
+}
+
+// DeletedPosts represents the top-level structure for a deleted posts API response,
+// returned in XML format when posts are requested with deleted=show.
+type DeletedPosts struct {
+	XMLName xml.Name      `xml:"posts"`
+	Post    []DeletedPost `xml:"post"`
+}
+
+// DeletedPost represents a single deleted post record.
+// The struct tags map the XML attributes from the API response to the fields.
+type DeletedPost struct {
+	XMLName  xml.Name `xml:"post"`
+	ID       int      `xml:"id,attr"`
+	MD5      string   `xml:"md5,attr"`
+	Deleted  bool     `xml:"deleted,attr"`
+	ParentID int      `xml:"parent_id,attr"`
+}
+
+// LastID returns the highest post ID among the deleted posts, or zero if there are none.
+// It can be passed to DeletedPostsRequestBuilder.LastID to fetch the next batch.
+func (d DeletedPosts) LastID() int {
+	lastID := 0
+	for _, post := range d.Post {
+		if post.ID > lastID {
+			lastID = post.ID
+		}
+	}
+
+	return lastID
+}
@@ -3,9 +3,17 @@ package rule34
 // Condition represents a single filtering rule, combining a type, an operator, and an argument.
 // For example, it can represent "score >= 10".
 type Condition struct {
-	FilteringType FilterType
-	Operation     Operator
-	Argument      int
+	FilteringType FilterType `json:"type"`
+	Operation     Operator   `json:"op"`
+	Argument      int        `json:"arg"`
+}
+
+// RangeCondition represents a filtering rule matching an inclusive range of values.
+// For example, it can represent "width:1920..3840".
+type RangeCondition struct {
+	FilteringType FilterType `json:"type"`
+	From          int        `json:"from"`
+	To            int        `json:"to"`
 }
 
 // FilterType represents a field that can be used for filtering, such as score, width, or height.
@@ -13,22 +21,24 @@ type FilterType string
 
 // Defines the valid filtering FilterTypes that can be used in API requests.
 const (
-	FilterByID      FilterType = "id"
-	FilterByScore   FilterType = "score"
-	FilterByHeight  FilterType = "height"
-	FilterByWidth   FilterType = "width"
-	FilterByParent  FilterType = "parent"
-	FilterByUpdated FilterType = "updated"
+	FilterByID       FilterType = "id"
+	FilterByScore    FilterType = "score"
+	FilterByHeight   FilterType = "height"
+	FilterByWidth    FilterType = "width"
+	FilterByParent   FilterType = "parent"
+	FilterByUpdated  FilterType = "updated"
+	FilterByTagCount FilterType = "tagcount"
 )
 
 // ValidFilterTypes is a set of all valid filtering FilterTypes for quick validation.
 var ValidFilterTypes = map[FilterType]struct{}{
-	FilterByID:      {},
-	FilterByScore:   {},
-	FilterByHeight:  {},
-	FilterByWidth:   {},
-	FilterByParent:  {},
-	FilterByUpdated: {},
+	FilterByID:       {},
+	FilterByScore:    {},
+	FilterByHeight:   {},
+	FilterByWidth:    {},
+	FilterByParent:   {},
+	FilterByUpdated:  {},
+	FilterByTagCount: {},
 }
 
 // IsValid checks if the filtering type is a valid, known type.
@@ -89,6 +99,31 @@ func (r Rating) IsValid() bool {
 	return ok
 }
 
+// PostStatus represents the moderation status of a post.
+type PostStatus string
+
+// Defines the valid post statuses.
+const (
+	StatusActive  PostStatus = "active"
+	StatusPending PostStatus = "pending"
+	StatusFlagged PostStatus = "flagged"
+	StatusDeleted PostStatus = "deleted"
+)
+
+// ValidPostStatuses is a set of all valid post statuses for quick validation.
+var ValidPostStatuses = map[PostStatus]struct{}{
+	StatusActive:  {},
+	StatusPending: {},
+	StatusFlagged: {},
+	StatusDeleted: {},
+}
+
+// IsValid checks if the post status is a valid, known status.
+func (s PostStatus) IsValid() bool {
+	_, ok := ValidPostStatuses[s]
+	return ok
+}
+
 // SortableType represents a field that can be used for sorting search results.
 type SortableType string
 
@@ -103,6 +138,7 @@ const (
 	SortByParent  SortableType = "parent"
 	SortBySource  SortableType = "source"
 	SortByUpdated SortableType = "updated"
+	SortByRandom  SortableType = "random"
 )
 
 // ValidSortableTypes is a set of all valid sortable types for quick validation.
@@ -116,6 +152,7 @@ var ValidSortableTypes = map[SortableType]struct{}{
 	SortByParent:  {},
 	SortBySource:  {},
 	SortByUpdated: {},
+	SortByRandom:  {},
 }
 
 // IsValid checks if the sorting type is a valid, known type.
@@ -123,3 +160,26 @@ func (t SortableType) IsValid() bool {
 	_, ok := ValidSortableTypes[t]
 	return ok
 }
+
+// TagSortableType represents a field that can be used for ordering tag search results.
+type TagSortableType string
+
+// Defines the valid tag sortable types for API requests.
+const (
+	TagSortByDate  TagSortableType = "date"
+	TagSortByCount TagSortableType = "count"
+	TagSortByName  TagSortableType = "name"
+)
+
+// ValidTagSortableTypes is a set of all valid tag sortable types for quick validation.
+var ValidTagSortableTypes = map[TagSortableType]struct{}{
+	TagSortByDate:  {},
+	TagSortByCount: {},
+	TagSortByName:  {},
+}
+
+// IsValid checks if the tag sorting type is a valid, known type.
+func (t TagSortableType) IsValid() bool {
+	_, ok := ValidTagSortableTypes[t]
+	return ok
+}
@@ -2,10 +2,13 @@
 package rule34
 
 import (
+	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -34,8 +37,35 @@ var (
 	ErrSortByWasCalledTwiceOrMore = errors.New("sort by was called twice or more")
 	// ErrTwoSortingOrders is returned when both Asc() and Desc() are called on the same builder.
 	ErrTwoSortingOrders = errors.New("both sorting orders was used")
+	// ErrSortingOrderOnRandom is returned when Asc() or Desc() is called after SortBy(SortByRandom).
+	ErrSortingOrderOnRandom = errors.New("random sorting has no order")
+	// ErrUnknownSortingOrder is returned when a sorting order other than "asc" or "desc" is provided.
+	ErrUnknownSortingOrder = errors.New("unknown sorting order was given")
+	// ErrInvalidRange is returned when the start of a range is greater than its end.
+	ErrInvalidRange = errors.New("range start can't be greater than its end")
+	// ErrInvalidMD5 is returned when a value that isn't an MD5 hex digest is provided.
+	ErrInvalidMD5 = errors.New("md5 must be 32 hexadecimal characters")
+	// ErrInvalidMetaValue is returned when a meta-tag value is empty or contains whitespace.
+	ErrInvalidMetaValue = errors.New("meta-tag value can't be empty or contain whitespace")
+	// ErrUnknownStatus is returned when an invalid post status is provided.
+	ErrUnknownStatus = errors.New("unknown post status was given")
+	// ErrTooFewOrTags is returned when AnyOf() is given less than two tags.
+	ErrTooFewOrTags = errors.New("or-group needs at least two tags")
+	// ErrInvalidTag is returned when a tag is empty or contains whitespace or query syntax characters.
+	ErrInvalidTag = errors.New("invalid tag was given")
+	// ErrNoWildcard is returned when TagPattern() is given a pattern without a '*' wildcard.
+	ErrNoWildcard = errors.New("tag pattern has no wildcard")
+	// ErrExclusionInTags is returned when a tag given to Tags() or BlackList() starts with '-'.
+	ErrExclusionInTags = errors.New("tag can't start with '-', use BlackList instead")
+	// ErrMetaTagInTags is returned when a meta-tag such as "rating:safe" is given as a plain tag.
+	ErrMetaTagInTags = errors.New("meta-tag can't be used as a plain tag, use the matching builder method")
+	// ErrContradictoryTags is returned when the same tag is both included and blacklisted.
+	ErrContradictoryTags = errors.New("tag is both included and blacklisted")
 )
 
+// aiGeneratedTag is the tag excluded by FilterAI.
+const aiGeneratedTag = "ai_generated"
+
 // PostsRequestBuilder is a builder for creating and executing API requests for posts.
 type PostsRequestBuilder struct {
 	options PostsOptions
@@ -50,13 +80,22 @@ type PostsOptions struct {
 	PageNumber          int
 	Tags                []string
 	BlackList           []string
+	OrGroups            [][]string
+	TagPatterns         []string
 	FilterAI            bool
 	Rating              Rating
 	ParentPostID        int
 	FilteringConditions []Condition
+	RangeConditions     []RangeCondition
+	MD5                 string
+	Source              string
+	User                string
+	Status              PostStatus
 	DoSort              bool
 	SortableType        SortableType
 	SortingOrder        string
+	MaxResults          int
+	CursorPostID        int
 }
 
 // PostID sets the specific post ID to retrieve.
@@ -94,21 +133,101 @@ func (b *PostsRequestBuilder) PageNumber(pageNumber int) *PostsRequestBuilder {
 }
 
 // Tags adds search tags to the request.
-// Multiple calls to this method will append tags.
+// Multiple calls to this method will append tags. Tags are normalized by lowercasing them
+// and replacing spaces with underscores, and duplicates are dropped.
 func (b *PostsRequestBuilder) Tags(tags ...string) *PostsRequestBuilder {
-	b.options.Tags = append(b.options.Tags, tags...)
+	for _, tag := range tags {
+		tag, err := cleanTag(tag)
+		if err != nil {
+			b.errors = append(b.errors, err)
+			continue
+		}
+
+		if b.isExcluded(tag) {
+			b.errors = append(b.errors, fmt.Errorf("%w: %s", ErrContradictoryTags, tag))
+			continue
+		}
+
+		if !slices.Contains(b.options.Tags, tag) {
+			b.options.Tags = append(b.options.Tags, tag)
+		}
+	}
+
 	return b
 }
 
 // BlackList adds tags to be excluded from the search results.
 // These tags will be prefixed with a '-' in the final query.
+// They are normalized and deduplicated the same way as in Tags.
 func (b *PostsRequestBuilder) BlackList(blackList ...string) *PostsRequestBuilder {
-	b.options.BlackList = append(b.options.BlackList, blackList...)
+	for _, tag := range blackList {
+		tag, err := cleanTag(tag)
+		if err != nil {
+			b.errors = append(b.errors, err)
+			continue
+		}
+
+		if slices.Contains(b.options.Tags, tag) {
+			b.errors = append(b.errors, fmt.Errorf("%w: %s", ErrContradictoryTags, tag))
+			continue
+		}
+
+		if !slices.Contains(b.options.BlackList, tag) {
+			b.options.BlackList = append(b.options.BlackList, tag)
+		}
+	}
+
+	return b
+}
+
+// AnyOf adds an OR-group matching posts that have at least one of the given tags.
+// It is serialized as "( a ~ b ~ c )" and combined with the other tags using AND.
+// The tags are normalized and validated the same way as in Tags.
+func (b *PostsRequestBuilder) AnyOf(tags ...string) *PostsRequestBuilder {
+	if len(tags) < 2 {
+		b.errors = append(b.errors, ErrTooFewOrTags)
+		return b
+	}
+
+	group := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag, err := cleanTag(tag)
+		if err != nil {
+			b.errors = append(b.errors, err)
+			return b
+		}
+		group = append(group, tag)
+	}
+
+	b.options.OrGroups = append(b.options.OrGroups, group)
+	return b
+}
+
+// TagPattern adds a tag pattern using '*' as a wildcard, for example "cat_*".
+// The pattern is normalized and validated the same way as in Tags.
+func (b *PostsRequestBuilder) TagPattern(pattern string) *PostsRequestBuilder {
+	pattern, err := cleanTag(pattern)
+	if err != nil {
+		b.errors = append(b.errors, err)
+		return b
+	}
+
+	if !strings.Contains(pattern, "*") {
+		b.errors = append(b.errors, ErrNoWildcard)
+		return b
+	}
+
+	b.options.TagPatterns = append(b.options.TagPatterns, pattern)
 	return b
 }
 
 // FilterAI adds a tag to exclude AI-generated content from the search results.
 func (b *PostsRequestBuilder) FilterAI() *PostsRequestBuilder {
+	if slices.Contains(b.options.Tags, aiGeneratedTag) {
+		b.errors = append(b.errors, fmt.Errorf("%w: %s", ErrContradictoryTags, aiGeneratedTag))
+		return b
+	}
+
 	b.options.FilterAI = true
 	return b
 }
@@ -159,6 +278,76 @@ func (b *PostsRequestBuilder) Where(ft FilterType, op Operator, arg int) *PostsR
 	return b
 }
 
+// WhereBetween adds a filtering condition matching an inclusive range of values.
+// For example, WhereBetween(FilterByWidth, 1920, 3840) finds posts with a width from 1920 to 3840.
+func (b *PostsRequestBuilder) WhereBetween(ft FilterType, from, to int) *PostsRequestBuilder {
+	if !ft.IsValid() {
+		b.errors = append(b.errors, ErrUnknownFilteringType)
+		return b
+	}
+
+	if from > to {
+		b.errors = append(b.errors, ErrInvalidRange)
+		return b
+	}
+
+	rangeCondition := RangeCondition{
+		FilteringType: ft,
+		From:          from,
+		To:            to,
+	}
+
+	b.options.RangeConditions = append(b.options.RangeConditions, rangeCondition)
+
+	return b
+}
+
+// MD5 searches for the post with the given MD5 hash of its file.
+func (b *PostsRequestBuilder) MD5(md5 string) *PostsRequestBuilder {
+	md5 = strings.ToLower(md5)
+	if !isMD5(md5) {
+		b.errors = append(b.errors, ErrInvalidMD5)
+		return b
+	}
+
+	b.options.MD5 = md5
+	return b
+}
+
+// Source searches for posts whose source matches the given value.
+// The value may contain '*' wildcards, for example "*pixiv*".
+func (b *PostsRequestBuilder) Source(source string) *PostsRequestBuilder {
+	if !isValidMetaValue(source) {
+		b.errors = append(b.errors, ErrInvalidMetaValue)
+		return b
+	}
+
+	b.options.Source = source
+	return b
+}
+
+// User searches for posts uploaded by the user with the given name.
+func (b *PostsRequestBuilder) User(user string) *PostsRequestBuilder {
+	if !isValidMetaValue(user) {
+		b.errors = append(b.errors, ErrInvalidMetaValue)
+		return b
+	}
+
+	b.options.User = user
+	return b
+}
+
+// Status searches for posts with the given moderation status.
+func (b *PostsRequestBuilder) Status(status PostStatus) *PostsRequestBuilder {
+	if !status.IsValid() {
+		b.errors = append(b.errors, ErrUnknownStatus)
+		return b
+	}
+
+	b.options.Status = status
+	return b
+}
+
 // SortBy specifies the field to sort the results by.
 // Must be called before Asc() or Desc(). Defaults to descending order if neither is called.
 func (b *PostsRequestBuilder) SortBy(sortableType SortableType) *PostsRequestBuilder {
@@ -209,39 +398,110 @@ func (b *PostsRequestBuilder) Desc() *PostsRequestBuilder {
 	return b
 }
 
-// Find executes the request to the API and returns the search results.
-// It first validates any accumulated errors, then builds the URL, performs the request, and unmarshals the response.
+// Find executes the request to the API with a background context. See FindContext.
 func (b *PostsRequestBuilder) Find() (Posts, error) {
+	return b.FindContext(context.Background())
+}
+
+// FindContext executes the request to the API and returns the search results.
+// It first validates any accumulated errors, then builds the URL, performs the request, and unmarshals the response.
+func (b *PostsRequestBuilder) FindContext(ctx context.Context) (Posts, error) {
 	if len(b.errors) != 0 {
 		err := errors.Join(b.errors...)
-		return nil, fmt.Errorf("invalid arguments: %v", err)
+		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
 
 	url, err := b.buildURL()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build url: %v", err)
+		return nil, fmt.Errorf("failed to build url: %w", err)
 	}
 
-	postsBytes, err := b.client.doRequest(url)
+	postsBytes, err := b.client.doRequest(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to do get posts request: %v", err)
+		return nil, fmt.Errorf("failed to do get posts request: %w", err)
 	}
 
 	posts, err := unmarshalPosts(postsBytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal posts: %v", err)
+		return nil, fmt.Errorf("failed to unmarshal posts: %w", err)
 	}
 
 	return posts, nil
 }
 
-// buildURL constructs the final request URL from the builder's options.
+// FindPage executes the request to the API with a background context. See FindPageContext.
+func (b *PostsRequestBuilder) FindPage() (PostsPage, error) {
+	return b.FindPageContext(context.Background())
+}
+
+// FindPageContext executes the request to the API in XML mode and returns a page of posts
+// together with the total number of matching posts and the page offset.
+func (b *PostsRequestBuilder) FindPageContext(ctx context.Context) (PostsPage, error) {
+	if len(b.errors) != 0 {
+		err := errors.Join(b.errors...)
+		return PostsPage{}, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	url, err := b.buildXMLURL()
+	if err != nil {
+		return PostsPage{}, fmt.Errorf("failed to build url: %w", err)
+	}
+
+	pageBytes, err := b.client.doRequest(ctx, url)
+	if err != nil {
+		return PostsPage{}, fmt.Errorf("failed to do get posts request: %w", err)
+	}
+
+	page, err := unmarshalPostsPage(pageBytes)
+	if err != nil {
+		return PostsPage{}, fmt.Errorf("failed to unmarshal posts page: %w", err)
+	}
+
+	return page, nil
+}
+
+// Count returns the total number of posts matching the request using a background context.
+// See CountContext.
+func (b *PostsRequestBuilder) Count() (int, error) {
+	return b.CountContext(context.Background())
+}
+
+// CountContext returns the total number of posts matching the request.
+// It performs a single XML request for one post and reads the count from the response envelope.
+func (b *PostsRequestBuilder) CountContext(ctx context.Context) (int, error) {
+	countBuilder := b.Clone()
+	countBuilder.options.Limit = 1
+
+	page, err := countBuilder.FindPageContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return page.Count, nil
+}
+
+// buildURL constructs the final JSON request URL from the builder's options.
 func (b *PostsRequestBuilder) buildURL() (string, error) {
 	u, err := url.Parse(b.client.baseURL)
 	if err != nil {
 		return "", fmt.Errorf("can't parse base URL: %w", err)
 	}
 
+	q := u.Query()
+	b.addOptions(&q)
+	q.Set("json", "1")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// buildXMLURL constructs the final XML request URL from the builder's options.
+func (b *PostsRequestBuilder) buildXMLURL() (string, error) {
+	u, err := url.Parse(b.client.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("can't parse base URL: %w", err)
+	}
+
 	q := u.Query()
 	b.addOptions(&q)
 	u.RawQuery = q.Encode()
@@ -270,77 +530,124 @@ func (b *PostsRequestBuilder) addOptions(q *url.Values) {
 
 	tags := b.convertTags()
 	q.Set("tags", tags)
-
-	q.Set("user_id", b.client.UserID)
-	q.Set("api_key", b.client.APIKey)
-
-	q.Set("json", "1")
 }
 
 // convertTags compiles all tags, blacklisted tags, and meta-tags into a single space-separated string.
 func (b *PostsRequestBuilder) convertTags() string {
 	sb := strings.Builder{}
 
-	for _, tag := range b.options.Tags {
-		sb.WriteString(fmt.Sprintf("%s ", tag))
+	for _, clause := range b.clauses() {
+		sb.WriteString(fmt.Sprintf("%s ", clause.Value))
 	}
 
-	for _, tag := range b.options.BlackList {
-		sb.WriteString(fmt.Sprintf("-%s ", tag))
-	}
+	return sb.String()
+}
 
-	if b.options.FilterAI {
-		sb.WriteString("-ai_generated ")
+// unmarshalPosts parses the JSON response body into a slice of Post models.
+func unmarshalPosts(postsBytes []byte) (Posts, error) {
+	if len(postsBytes) == 0 {
+		return Posts{}, nil
 	}
 
-	if b.options.Rating != "" {
-		sb.WriteString(fmt.Sprintf("rating:%s ", b.options.Rating))
+	var posts Posts
+
+	err := json.Unmarshal(postsBytes, &posts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal posts: %w", err)
 	}
 
-	parentPostID := strconv.Itoa(b.options.ParentPostID)
-	if parentPostID != "0" {
-		sb.WriteString(fmt.Sprintf("parent:%s ", parentPostID))
+	return posts, nil
+}
+
+// unmarshalPostsPage parses the XML response body into a PostsPage model.
+func unmarshalPostsPage(pageBytes []byte) (PostsPage, error) {
+	if len(pageBytes) == 0 {
+		return PostsPage{Posts: Posts{}}, nil
 	}
 
-	if len(b.options.FilteringConditions) != 0 {
-		for _, fc := range b.options.FilteringConditions {
-			sb.WriteString(fmt.Sprintf("%s:%s%d ", fc.FilteringType, fc.Operation, fc.Argument))
-		}
+	var page PostsPage
+
+	err := xml.Unmarshal(pageBytes, &page)
+	if err != nil {
+		return PostsPage{}, fmt.Errorf("failed to unmarshal posts page: %w", err)
 	}
 
-	if b.options.DoSort {
-		if b.options.SortingOrder != "" {
-			sb.WriteString(fmt.Sprintf("sort:%s:%s ", b.options.SortableType, b.options.SortingOrder))
-		} else {
-			sb.WriteString(fmt.Sprintf("sort:%s:desc ", b.options.SortableType))
-		}
+	return page, nil
+}
+
+// normalizeTag lowercases the tag, trims it and replaces runs of whitespace with a single underscore,
+// which is how the site spells multi-word tags.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), "_"))
+}
+
+// cleanTag normalizes a tag and validates the result. It is the single path every tag
+// given to Tags, BlackList, AnyOf or TagPattern goes through.
+func cleanTag(tag string) (string, error) {
+	tag = normalizeTag(tag)
+	if err := validateTag(tag); err != nil {
+		return "", err
 	}
 
-	return sb.String()
+	return tag, nil
 }
 
-// unmarshalPosts parses the JSON response body into a slice of Post models.
-func unmarshalPosts(postsBytes []byte) (Posts, error) {
-	if len(postsBytes) == 0 {
-		return Posts{}, nil
+// validateTag checks that a normalized tag is a plain tag rather than an exclusion,
+// a meta-tag or a piece of query syntax.
+func validateTag(tag string) error {
+	if tag == "" || tag == "(" || tag == ")" || strings.HasPrefix(tag, "~") {
+		return fmt.Errorf("%w: %q", ErrInvalidTag, tag)
 	}
 
-	var posts Posts
+	if strings.HasPrefix(tag, "-") {
+		return fmt.Errorf("%w: %s", ErrExclusionInTags, tag)
+	}
 
-	err := json.Unmarshal(postsBytes, &posts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal posts: %v", err)
+	if name, _, ok := strings.Cut(tag, ":"); ok && isMetaTagName(name) {
+		return fmt.Errorf("%w: %s", ErrMetaTagInTags, tag)
 	}
 
-	return posts, nil
+	return nil
 }
 
-// checkDoSort ensures that SortBy has been called before a sorting order method (Asc/Desc) is used.
+// isMD5 reports whether the value is a lowercase MD5 hex digest.
+func isMD5(value string) bool {
+	if len(value) != 32 {
+		return false
+	}
+
+	for _, r := range value {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidMetaValue reports whether the value can be used as the value of a meta-tag,
+// which means it is non-empty and contains no whitespace.
+func isValidMetaValue(value string) bool {
+	return value != "" && len(strings.Fields(value)) == 1 && strings.TrimSpace(value) == value
+}
+
+// isExcluded reports whether the tag is excluded from the search, either blacklisted or filtered out by FilterAI.
+func (b *PostsRequestBuilder) isExcluded(tag string) bool {
+	return slices.Contains(b.options.BlackList, tag) || (b.options.FilterAI && tag == aiGeneratedTag)
+}
+
+// checkDoSort ensures that SortBy has been called before a sorting order method (Asc/Desc) is used,
+// and that the sorting type has an order at all, which random sorting doesn't.
 func (b *PostsRequestBuilder) checkDoSort() bool {
 	if !b.options.DoSort {
 		b.errors = append(b.errors, ErrSortByWasNotCalled)
 		return false
 	}
 
+	if b.options.SortableType == SortByRandom {
+		b.errors = append(b.errors, ErrSortingOrderOnRandom)
+		return false
+	}
+
 	return true
 }
@@ -0,0 +1,357 @@
+package rule34
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Pre-defined errors for parsing raw tag queries.
+var (
+	// ErrUnknownMetaTag is returned when a query contains a token that looks like a meta-tag,
+	// such as "scroe:>=50", but has a name the builder doesn't support.
+	ErrUnknownMetaTag = errors.New("unknown meta-tag")
+	// ErrMalformedMetaTag is returned when a known meta-tag has an invalid value.
+	ErrMalformedMetaTag = errors.New("malformed meta-tag")
+	// ErrMalformedOrGroup is returned when an OR-group of a query isn't closed or has misplaced separators.
+	ErrMalformedOrGroup = errors.New("malformed or-group")
+)
+
+// QueryError describes a problem with a single token of a raw tag query.
+type QueryError struct {
+	Pos   int    // Byte offset of the token in the query
+	Token string // The offending token
+	Err   error  // The underlying error
+}
+
+// Error implements the error interface.
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("position %d: %q: %v", e.Pos, e.Token, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// queryToken is a whitespace-separated token of a raw tag query together with its position.
+type queryToken struct {
+	pos   int
+	value string
+}
+
+// ParseQuery turns a raw tag query such as "cat_girl -solo rating:safe score:>=50 sort:score:asc"
+// into a PostsRequestBuilder of the client. Plain tags become Tags, "-tag" becomes BlackList,
+// "( a ~ b )" becomes AnyOf, tags with a '*' wildcard become TagPattern, and the rating, parent,
+// sort, md5, source, user, status, filtering and range meta-tags are mapped onto the matching builder calls.
+// It returns the builder together with the errors of all unknown or malformed tokens,
+// each wrapped in a *QueryError carrying its position.
+func (c *Client) ParseQuery(query string) (*PostsRequestBuilder, error) {
+	b := c.Posts()
+	err := b.Query(query)
+	return b, err
+}
+
+// ParseQuery turns a raw tag query into PostsOptions without a client. See Client.ParseQuery.
+func ParseQuery(query string) (PostsOptions, error) {
+	b, err := (&Client{}).ParseQuery(query)
+	if err != nil {
+		return PostsOptions{}, err
+	}
+
+	return b.options, nil
+}
+
+// Query parses a raw tag query and applies it to the builder. It is the inverse of the tag string
+// the builder sends to the API. Invalid tokens are recorded in the builder's errors and also returned,
+// each wrapped in a *QueryError carrying its position.
+func (b *PostsRequestBuilder) Query(query string) error {
+	var errs []error
+
+	tokens := tokenizeQuery(query)
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		errsBefore := len(b.errors)
+
+		if token.value == "(" {
+			group, end, err := parseOrGroup(tokens, i)
+			if err != nil {
+				b.errors = append(b.errors, err)
+			} else {
+				b.AnyOf(group...)
+			}
+			i = end
+		} else if err := b.applyQueryToken(token.value); err != nil {
+			b.errors = append(b.errors, err)
+		}
+
+		for _, err := range b.errors[errsBefore:] {
+			errs = append(errs, &QueryError{Pos: token.pos, Token: token.value, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseOrGroup collects the tags of the OR-group opened by the token at index start.
+// It returns the tags and the index of the closing token, or of the last token if the group isn't closed.
+func parseOrGroup(tokens []queryToken, start int) ([]string, int, error) {
+	var group []string
+	expectTag := true
+	malformed := false
+
+	for i := start + 1; i < len(tokens); i++ {
+		switch value := tokens[i].value; {
+		case value == ")":
+			if malformed || expectTag {
+				return nil, i, ErrMalformedOrGroup
+			}
+			return group, i, nil
+		case value == "~":
+			malformed = malformed || expectTag
+			expectTag = true
+		default:
+			malformed = malformed || !expectTag
+			group = append(group, value)
+			expectTag = false
+		}
+	}
+
+	return nil, len(tokens) - 1, ErrMalformedOrGroup
+}
+
+// applyQueryToken maps a single query token onto the matching builder call.
+// Errors of the builder call itself are recorded in the builder's errors as usual.
+func (b *PostsRequestBuilder) applyQueryToken(token string) error {
+	if excluded, ok := strings.CutPrefix(token, "-"); ok {
+		if excluded == "" {
+			return ErrMalformedMetaTag
+		}
+
+		if excluded == aiGeneratedTag {
+			b.FilterAI()
+			return nil
+		}
+
+		b.BlackList(excluded)
+		return nil
+	}
+
+	name, value, ok := strings.Cut(token, ":")
+	if !ok {
+		b.applyPlainToken(token)
+		return nil
+	}
+
+	if apply, ok := metaTagApplier(name); ok {
+		return apply(b, name, value)
+	}
+
+	if looksLikeMetaTag(name, value) {
+		return ErrUnknownMetaTag
+	}
+
+	b.applyPlainToken(token)
+	return nil
+}
+
+// metaTagApplyFunc applies the value of a meta-tag to the builder.
+type metaTagApplyFunc func(b *PostsRequestBuilder, name, value string) error
+
+// metaTags maps the names of the meta-tags the builder has a method for, other than the
+// filtering types, to the function applying them. Together with ValidFilterTypes it is the
+// single list of meta-tag names used by both the query parser and tag validation.
+var metaTags = map[string]metaTagApplyFunc{
+	"rating": func(b *PostsRequestBuilder, _, value string) error {
+		b.Rating(Rating(value))
+		return nil
+	},
+	"parent": func(b *PostsRequestBuilder, name, value string) error {
+		// A bare post ID comes from ParentPostID, while comparisons and ranges
+		// such as "parent:>5" or "parent:1..5" come from filtering by FilterByParent.
+		if value != "" && (strings.ContainsRune("<>=!", rune(value[0])) || strings.Contains(value, "..")) {
+			return applyFilterToken(b, name, value)
+		}
+
+		parentPostID, err := strconv.Atoi(value)
+		if err != nil {
+			return ErrMalformedMetaTag
+		}
+		b.ParentPostID(parentPostID)
+		return nil
+	},
+	"sort": func(b *PostsRequestBuilder, _, value string) error {
+		return b.applySortToken(value)
+	},
+	"md5": func(b *PostsRequestBuilder, _, value string) error {
+		b.MD5(value)
+		return nil
+	},
+	"source": func(b *PostsRequestBuilder, _, value string) error {
+		b.Source(value)
+		return nil
+	},
+	"user": func(b *PostsRequestBuilder, _, value string) error {
+		b.User(value)
+		return nil
+	},
+	"status": func(b *PostsRequestBuilder, _, value string) error {
+		b.Status(PostStatus(value))
+		return nil
+	},
+}
+
+// metaTagApplier returns the function applying the meta-tag with the given name,
+// and reports whether the name is a meta-tag the builder has a method for.
+func metaTagApplier(name string) (metaTagApplyFunc, bool) {
+	if apply, ok := metaTags[name]; ok {
+		return apply, true
+	}
+
+	if FilterType(name).IsValid() {
+		return applyFilterToken, true
+	}
+
+	return nil, false
+}
+
+// isMetaTagName reports whether the name is a meta-tag prefix the builder has a method for.
+// Other prefixes are left alone, since regular tags such as "re:zero" may contain a colon.
+func isMetaTagName(name string) bool {
+	_, ok := metaTagApplier(name)
+	return ok
+}
+
+// applyFilterToken applies the value of a filtering meta-tag, either a comparison such as ">=50"
+// or a range such as "1920..3840".
+func applyFilterToken(b *PostsRequestBuilder, name, value string) error {
+	if strings.Contains(value, "..") {
+		from, to, err := parseRange(value)
+		if err != nil {
+			return err
+		}
+		b.WhereBetween(FilterType(name), from, to)
+		return nil
+	}
+
+	op, arg, err := parseCondition(value)
+	if err != nil {
+		return err
+	}
+	b.Where(FilterType(name), op, arg)
+	return nil
+}
+
+// applyPlainToken adds a token that isn't a meta-tag, such as "cat_girl" or "re:zero",
+// as a tag, or as a tag pattern if it contains a '*' wildcard.
+func (b *PostsRequestBuilder) applyPlainToken(token string) {
+	if strings.Contains(token, "*") {
+		b.TagPattern(token)
+		return
+	}
+
+	b.Tags(token)
+}
+
+// looksLikeMetaTag reports whether a token with an unknown prefix is most likely a mistyped meta-tag
+// rather than a tag containing a colon. That is the case when the prefix is a plain word and the value
+// is a comparison such as ">=50" or a range such as "10..20". Tags like "re:zero" or ":3" don't qualify.
+func looksLikeMetaTag(name, value string) bool {
+	if name == "" || strings.IndexFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && r != '_' }) != -1 {
+		return false
+	}
+
+	if value != "" && strings.ContainsRune("<>=!", rune(value[0])) {
+		return true
+	}
+
+	_, _, err := parseRange(value)
+	return strings.Contains(value, "..") && err == nil
+}
+
+// applySortToken applies the value of a sort meta-tag, such as "score:asc".
+func (b *PostsRequestBuilder) applySortToken(value string) error {
+	sortableType, order, _ := strings.Cut(value, ":")
+	if sortableType == "" {
+		return ErrMalformedMetaTag
+	}
+
+	b.SortBy(SortableType(sortableType))
+
+	switch order {
+	case "":
+	case "asc":
+		b.Asc()
+	case "desc":
+		b.Desc()
+	default:
+		return ErrMalformedMetaTag
+	}
+
+	return nil
+}
+
+// parseCondition parses the value of a filtering meta-tag, such as ">=50", into an operator and an argument.
+// A value without an operator means equality.
+func parseCondition(value string) (Operator, int, error) {
+	op := Equal
+	for _, candidate := range []Operator{GreaterEqual, LessEqual, NotEqual, Greater, Less, Equal} {
+		if rest, ok := strings.CutPrefix(value, string(candidate)); ok {
+			op = candidate
+			value = rest
+			break
+		}
+	}
+
+	arg, err := strconv.Atoi(value)
+	if err != nil {
+		return "", 0, ErrMalformedMetaTag
+	}
+
+	return op, arg, nil
+}
+
+// parseRange parses the value of a range meta-tag, such as "1920..3840", into its inclusive bounds.
+func parseRange(value string) (int, int, error) {
+	fromStr, toStr, _ := strings.Cut(value, "..")
+
+	from, err := strconv.Atoi(fromStr)
+	if err != nil {
+		return 0, 0, ErrMalformedMetaTag
+	}
+
+	to, err := strconv.Atoi(toStr)
+	if err != nil {
+		return 0, 0, ErrMalformedMetaTag
+	}
+
+	return from, to, nil
+}
+
+// tokenizeQuery splits a raw tag query on whitespace, remembering the byte offset of every token.
+func tokenizeQuery(query string) []queryToken {
+	var tokens []queryToken
+
+	start := -1
+	for i, r := range query {
+		if unicode.IsSpace(r) {
+			if start != -1 {
+				tokens = append(tokens, queryToken{pos: start, value: query[start:i]})
+				start = -1
+			}
+			continue
+		}
+
+		if start == -1 {
+			start = i
+		}
+	}
+
+	if start != -1 {
+		tokens = append(tokens, queryToken{pos: start, value: query[start:]})
+	}
+
+	return tokens
+}
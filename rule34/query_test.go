@@ -0,0 +1,250 @@
+package rule34
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseQueryMapsMetaTags(t *testing.T) {
+	o, err := ParseQuery("cat_girl -solo rating:safe score:>=50 sort:score:asc parent:123 -ai_generated")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(o.Tags, []string{"cat_girl"}) {
+		t.Errorf("tags = %v, want [cat_girl]", o.Tags)
+	}
+	if !slices.Equal(o.BlackList, []string{"solo"}) {
+		t.Errorf("blacklist = %v, want [solo]", o.BlackList)
+	}
+	if !o.FilterAI {
+		t.Error("filter ai = false, want true")
+	}
+	if o.Rating != Safe {
+		t.Errorf("rating = %q, want %q", o.Rating, Safe)
+	}
+	if o.ParentPostID != 123 {
+		t.Errorf("parent post id = %d, want 123", o.ParentPostID)
+	}
+
+	wantCondition := Condition{FilteringType: FilterByScore, Operation: GreaterEqual, Argument: 50}
+	if !slices.Equal(o.FilteringConditions, []Condition{wantCondition}) {
+		t.Errorf("conditions = %v, want [%v]", o.FilteringConditions, wantCondition)
+	}
+	if !o.DoSort || o.SortableType != SortByScore || o.SortingOrder != "asc" {
+		t.Errorf("sort = %v %q %q, want score asc", o.DoSort, o.SortableType, o.SortingOrder)
+	}
+}
+
+func TestParseQueryOperators(t *testing.T) {
+	tests := []struct {
+		query string
+		want  Condition
+	}{
+		{"score:50", Condition{FilterByScore, Equal, 50}},
+		{"score:=50", Condition{FilterByScore, Equal, 50}},
+		{"score:!=50", Condition{FilterByScore, NotEqual, 50}},
+		{"score:>50", Condition{FilterByScore, Greater, 50}},
+		{"score:>=50", Condition{FilterByScore, GreaterEqual, 50}},
+		{"score:<50", Condition{FilterByScore, Less, 50}},
+		{"score:<=50", Condition{FilterByScore, LessEqual, 50}},
+		{"score:>=-5", Condition{FilterByScore, GreaterEqual, -5}},
+	}
+
+	for _, tt := range tests {
+		o, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.query, err)
+			continue
+		}
+
+		if !slices.Equal(o.FilteringConditions, []Condition{tt.want}) {
+			t.Errorf("%q: conditions = %v, want [%v]", tt.query, o.FilteringConditions, tt.want)
+		}
+	}
+}
+
+func TestParseQueryKeepsColonTags(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"re:zero", []string{"re:zero"}},
+		{":3", []string{":3"}},
+		{"nier:automata 2b", []string{"nier:automata", "2b"}},
+		{"foo:123", []string{"foo:123"}},
+	}
+
+	for _, tt := range tests {
+		o, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.query, err)
+			continue
+		}
+
+		if !slices.Equal(o.Tags, tt.want) {
+			t.Errorf("%q: tags = %v, want %v", tt.query, o.Tags, tt.want)
+		}
+	}
+}
+
+func TestParseQueryWildcardsAndOrGroups(t *testing.T) {
+	o, err := ParseQuery("a ( b ~ c ~ saber_(fate) ) cat_* re:*")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(o.Tags, []string{"a"}) {
+		t.Errorf("tags = %v, want [a]", o.Tags)
+	}
+	if !slices.Equal(o.TagPatterns, []string{"cat_*", "re:*"}) {
+		t.Errorf("tag patterns = %v, want [cat_* re:*]", o.TagPatterns)
+	}
+	if len(o.OrGroups) != 1 || !slices.Equal(o.OrGroups[0], []string{"b", "c", "saber_(fate)"}) {
+		t.Errorf("or-groups = %v, want [[b c saber_(fate)]]", o.OrGroups)
+	}
+}
+
+func TestParseQueryReportsErrorsWithPositions(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantErr error
+		wantPos int
+		wantTok string
+	}{
+		{"cat scroe:>=50", ErrUnknownMetaTag, 4, "scroe:>=50"},
+		{"cat  widht:10..20", ErrUnknownMetaTag, 5, "widht:10..20"},
+		{"parent:abc", ErrMalformedMetaTag, 0, "parent:abc"},
+		{"a sort:score:sideways", ErrMalformedMetaTag, 2, "sort:score:sideways"},
+		{"width:x", ErrMalformedMetaTag, 0, "width:x"},
+		{"width:1..x", ErrMalformedMetaTag, 0, "width:1..x"},
+		{"rating:spicy", ErrUnknownRating, 0, "rating:spicy"},
+		{"sort:nothing", ErrUnknownSortingType, 0, "sort:nothing"},
+		{"sort:random:asc", ErrSortingOrderOnRandom, 0, "sort:random:asc"},
+		{"md5:zz", ErrInvalidMD5, 0, "md5:zz"},
+		{"status:lost", ErrUnknownStatus, 0, "status:lost"},
+		{"width:10..1", ErrInvalidRange, 0, "width:10..1"},
+		{"x -", ErrMalformedMetaTag, 2, "-"},
+		{"( b ~ ) z", ErrMalformedOrGroup, 0, "("},
+		{"z ( b c )", ErrMalformedOrGroup, 2, "("},
+		{"( b ~ c", ErrMalformedOrGroup, 0, "("},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.query)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%q: error = %v, want %v", tt.query, err, tt.wantErr)
+			continue
+		}
+
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) {
+			t.Errorf("%q: error %v is not a *QueryError", tt.query, err)
+			continue
+		}
+
+		if queryErr.Pos != tt.wantPos || queryErr.Token != tt.wantTok {
+			t.Errorf("%q: error at %d %q, want %d %q", tt.query, queryErr.Pos, queryErr.Token, tt.wantPos, tt.wantTok)
+		}
+	}
+}
+
+func TestParseQueryReportsEveryInvalidToken(t *testing.T) {
+	b, err := New("", "").ParseQuery("parent:x ok scroe:>1")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("error %v doesn't wrap several errors", err)
+	}
+
+	var positions []int
+	for _, err := range joined.Unwrap() {
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) {
+			t.Errorf("error %v is not a *QueryError", err)
+			continue
+		}
+		positions = append(positions, queryErr.Pos)
+	}
+
+	if want := []int{0, 12}; !slices.Equal(positions, want) {
+		t.Errorf("error positions = %v, want %v", positions, want)
+	}
+
+	if !slices.Equal(b.options.Tags, []string{"ok"}) {
+		t.Errorf("tags = %v, want [ok]", b.options.Tags)
+	}
+
+	if _, err := b.Find(); err == nil {
+		t.Error("Find succeeded on a builder with query errors")
+	}
+}
+
+func TestParseQueryIsInverseOfTagString(t *testing.T) {
+	queries := []string{
+		"cat_girl re:zero cat_* ( a ~ b ) -solo -ai_generated rating:safe parent:123 score:>=50 sort:score:asc",
+		"parent:>5",
+		"parent:=7",
+		"parent:1..5",
+	}
+
+	for _, query := range queries {
+		o, err := ParseQuery(query)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", query, err)
+			continue
+		}
+
+		if got := o.TagString(); got != query {
+			t.Errorf("tag string = %q, want %q", got, query)
+		}
+	}
+}
+
+func TestParseQueryExpandedMetaTags(t *testing.T) {
+	o, err := ParseQuery("width:1920..3840 md5:0123456789ABCDEF0123456789abcdef source:*pixiv* user:bob status:active tagcount:>10 sort:random")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantRange := RangeCondition{FilteringType: FilterByWidth, From: 1920, To: 3840}
+	if !slices.Equal(o.RangeConditions, []RangeCondition{wantRange}) {
+		t.Errorf("ranges = %v, want [%v]", o.RangeConditions, wantRange)
+	}
+	if o.MD5 != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("md5 = %q, want lowercase digest", o.MD5)
+	}
+	if o.Source != "*pixiv*" || o.User != "bob" || o.Status != StatusActive {
+		t.Errorf("source, user, status = %q %q %q", o.Source, o.User, o.Status)
+	}
+
+	wantCondition := Condition{FilteringType: FilterByTagCount, Operation: Greater, Argument: 10}
+	if !slices.Equal(o.FilteringConditions, []Condition{wantCondition}) {
+		t.Errorf("conditions = %v, want [%v]", o.FilteringConditions, wantCondition)
+	}
+	if got := o.TagString(); !strings.HasSuffix(got, " sort:random") {
+		t.Errorf("tag string = %q, want it to end with sort:random", got)
+	}
+}
+
+func TestTagsRejectEveryMetaTagName(t *testing.T) {
+	var names []string
+	for name := range metaTags {
+		names = append(names, name)
+	}
+	for ft := range ValidFilterTypes {
+		names = append(names, string(ft))
+	}
+
+	for _, name := range names {
+		b := New("", "").Posts().Tags(name + ":x")
+		if len(b.errors) != 1 || !errors.Is(b.errors[0], ErrMetaTagInTags) {
+			t.Errorf("Tags(%q) errors = %v, want %v", name+":x", b.errors, ErrMetaTagInTags)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package rule34
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Pre-defined errors for the rate limiter.
+var (
+	// ErrNonPositiveRate is returned when a non-positive rate is provided for the rate limiter.
+	ErrNonPositiveRate = errors.New("rate can't be less than or equal to zero")
+	// ErrNonPositiveBurst is returned when a non-positive burst is provided for the rate limiter.
+	ErrNonPositiveBurst = errors.New("burst can't be less than or equal to zero")
+)
+
+// RateLimiter is a token bucket rate limiter shared by all requests of a client.
+// The bucket holds up to burst tokens and is refilled at the given rate per second;
+// every request takes one token and waits until one is available.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	stats  RateLimiterStats
+}
+
+// RateLimiterStats holds counters describing how the rate limiter has been used.
+type RateLimiterStats struct {
+	Requests  int64         // Number of requests that passed the limiter
+	Delayed   int64         // Number of requests that had to wait for a token
+	Canceled  int64         // Number of requests whose context was done while waiting
+	TotalWait time.Duration // Total time requests spent waiting for tokens
+}
+
+// NewRateLimiter creates a rate limiter allowing rps requests per second with bursts of up to burst requests.
+// The bucket starts full.
+func NewRateLimiter(rps float64, burst int) (*RateLimiter, error) {
+	if rps <= 0 {
+		return nil, ErrNonPositiveRate
+	}
+
+	if burst <= 0 {
+		return nil, ErrNonPositiveBurst
+	}
+
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}, nil
+}
+
+// SetRateLimit limits all requests of the client to rps requests per second with bursts of up to burst requests.
+// It isn't safe to call while requests are in flight; prefer WithRateLimit when creating the client.
+func (c *Client) SetRateLimit(rps float64, burst int) error {
+	limiter, err := NewRateLimiter(rps, burst)
+	if err != nil {
+		return err
+	}
+
+	c.rateLimiter = limiter
+	return nil
+}
+
+// RateLimiterStats returns the statistics of the client's rate limiter.
+// It returns zero statistics if no rate limit is set.
+func (c *Client) RateLimiterStats() RateLimiterStats {
+	if c.rateLimiter == nil {
+		return RateLimiterStats{}
+	}
+
+	return c.rateLimiter.Stats()
+}
+
+// Wait blocks until a token is available or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.refill(time.Now())
+	l.tokens--
+	wait := time.Duration(0)
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait == 0 {
+		l.record(func(s *RateLimiterStats) { s.Requests++ })
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.stats.Canceled++
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		l.record(func(s *RateLimiterStats) {
+			s.Requests++
+			s.Delayed++
+			s.TotalWait += wait
+		})
+		return nil
+	}
+}
+
+// Stats returns a snapshot of the rate limiter statistics.
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.stats
+}
+
+// refill adds the tokens accumulated since the last refill, up to the burst size.
+// It must be called with the mutex held.
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+
+	l.tokens += elapsed * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// record updates the statistics under the mutex.
+func (l *RateLimiter) record(update func(s *RateLimiterStats)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	update(&l.stats)
+}
@@ -0,0 +1,106 @@
+package rule34
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiterRejectsNonPositiveValues(t *testing.T) {
+	if _, err := NewRateLimiter(0, 1); !errors.Is(err, ErrNonPositiveRate) {
+		t.Errorf("error = %v, want ErrNonPositiveRate", err)
+	}
+
+	if _, err := NewRateLimiter(1, 0); !errors.Is(err, ErrNonPositiveBurst) {
+		t.Errorf("error = %v, want ErrNonPositiveBurst", err)
+	}
+}
+
+func TestWithRateLimitInvalidValuesFailRequests(t *testing.T) {
+	server, attempts := failingServer(t, 0, http.StatusOK, 0)
+
+	c := New("1", "key", WithRateLimit(-1, 1))
+	if _, err := c.doRequest(context.Background(), testBaseURL(server)); !errors.Is(err, ErrNonPositiveRate) {
+		t.Errorf("error = %v, want it to match ErrNonPositiveRate", err)
+	}
+
+	if got := attempts.Load(); got != 0 {
+		t.Errorf("attempts = %d, want 0", got)
+	}
+}
+
+func TestRateLimiterStats(t *testing.T) {
+	limiter, err := NewRateLimiter(50, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stats := limiter.Stats()
+	if stats.Requests != 3 || stats.Delayed != 1 || stats.Canceled != 0 {
+		t.Errorf("stats = %+v, want 3 requests with 1 delayed", stats)
+	}
+
+	if stats.TotalWait <= 0 || stats.TotalWait > 20*time.Millisecond {
+		t.Errorf("total wait = %v, want about one token at 50 per second", stats.TotalWait)
+	}
+}
+
+func TestRateLimiterCancellationGivesTokenBack(t *testing.T) {
+	limiter, err := NewRateLimiter(5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+
+	if stats := limiter.Stats(); stats.Canceled != 1 || stats.Requests != 1 {
+		t.Errorf("stats = %+v, want 1 request and 1 canceled", stats)
+	}
+
+	// Without the token given back, the next request would wait for two tokens, 400ms.
+	start := time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("waited %v, want about 200ms for a single token", elapsed)
+	}
+}
+
+func TestClientRateLimiterStats(t *testing.T) {
+	server, _ := failingServer(t, 0, http.StatusOK, 0)
+
+	c := New("1", "key")
+	if stats := c.RateLimiterStats(); stats != (RateLimiterStats{}) {
+		t.Errorf("stats without rate limit = %+v, want zero", stats)
+	}
+
+	c = New("1", "key", WithRateLimit(1000, 5))
+	for range 2 {
+		if _, err := c.doRequest(context.Background(), testBaseURL(server)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if stats := c.RateLimiterStats(); stats.Requests != 2 {
+		t.Errorf("requests = %d, want 2", stats.Requests)
+	}
+}
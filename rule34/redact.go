@@ -0,0 +1,130 @@
+package rule34
+
+import (
+	"errors"
+	"net/url"
+)
+
+// redactedValue replaces credential values in redacted URLs.
+const redactedValue = "REDACTED"
+
+// credentialParams lists the query parameters that carry credentials.
+var credentialParams = []string{"user_id", "api_key"}
+
+// redactURL returns the URL with the values of all credential query parameters replaced.
+// If the URL can't be parsed, a placeholder is returned so the credentials never leak.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<unparsable url>"
+	}
+
+	q := u.Query()
+	for _, param := range credentialParams {
+		if q.Has(param) {
+			q.Set(param, redactedValue)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// redactedRequestURL returns the request URL as it would be sent by the client,
+// with placeholders in place of the credentials the client would add.
+func (c *Client) redactedRequestURL(rawURL string) string {
+	if c.IsAnonymous() {
+		return redactURL(rawURL)
+	}
+
+	withPlaceholders, err := withCredentials(rawURL, Credentials{UserID: redactedValue, APIKey: redactedValue})
+	if err != nil {
+		return redactURL(rawURL)
+	}
+
+	return withPlaceholders
+}
+
+// redactError masks the credentials in the URL embedded by a *url.Error,
+// which net/http returns for failed requests.
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(urlErr.URL)
+	}
+
+	return err
+}
+
+// RedactedURL returns the URL the request would be sent to, with credentials masked.
+// It is meant for debugging and logging and makes no network call.
+func (b *PostsRequestBuilder) RedactedURL() (string, error) {
+	u, err := b.buildURL()
+	if err != nil {
+		return "", err
+	}
+
+	return b.client.redactedRequestURL(u), nil
+}
+
+// String implements the fmt.Stringer interface by returning the redacted request URL.
+func (b *PostsRequestBuilder) String() string {
+	return stringOfRedactedURL(b.RedactedURL())
+}
+
+// RedactedURL returns the URL the request would be sent to, with credentials masked.
+// It is meant for debugging and logging and makes no network call.
+func (b *CommentsRequestBuilder) RedactedURL() (string, error) {
+	u, err := b.buildURL()
+	if err != nil {
+		return "", err
+	}
+
+	return b.client.redactedRequestURL(u), nil
+}
+
+// String implements the fmt.Stringer interface by returning the redacted request URL.
+func (b *CommentsRequestBuilder) String() string {
+	return stringOfRedactedURL(b.RedactedURL())
+}
+
+// RedactedURL returns the URL the request would be sent to, with credentials masked.
+// It is meant for debugging and logging and makes no network call.
+func (b *TagsRequestBuilder) RedactedURL() (string, error) {
+	u, err := b.buildURL()
+	if err != nil {
+		return "", err
+	}
+
+	return b.client.redactedRequestURL(u), nil
+}
+
+// String implements the fmt.Stringer interface by returning the redacted request URL.
+func (b *TagsRequestBuilder) String() string {
+	return stringOfRedactedURL(b.RedactedURL())
+}
+
+// RedactedURL returns the URL the request would be sent to, with credentials masked.
+// It is meant for debugging and logging and makes no network call.
+func (b *DeletedPostsRequestBuilder) RedactedURL() (string, error) {
+	u, err := b.buildURL()
+	if err != nil {
+		return "", err
+	}
+
+	return b.client.redactedRequestURL(u), nil
+}
+
+// String implements the fmt.Stringer interface by returning the redacted request URL.
+func (b *DeletedPostsRequestBuilder) String() string {
+	return stringOfRedactedURL(b.RedactedURL())
+}
+
+// stringOfRedactedURL formats the result of a RedactedURL call for String methods.
+func stringOfRedactedURL(u string, err error) string {
+	if err != nil {
+		return "<invalid url: " + err.Error() + ">"
+	}
+
+	return u
+}
@@ -0,0 +1,140 @@
+package rule34
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testUserID = "4815162342"
+	testAPIKey = "0123456789abcdef-secret-api-key"
+)
+
+// assertNoCredentials fails the test if the string contains the test credentials, or for
+// anonymous clients any credential parameter at all.
+func assertNoCredentials(t *testing.T, what, s string, anonymous bool) {
+	t.Helper()
+
+	for _, secret := range []string{testUserID, testAPIKey} {
+		if strings.Contains(s, secret) {
+			t.Errorf("%s contains credential %q: %s", what, secret, s)
+		}
+	}
+
+	if anonymous {
+		for _, param := range credentialParams {
+			if strings.Contains(s, param+"=") {
+				t.Errorf("%s of an anonymous client contains %s: %s", what, param, s)
+			}
+		}
+	}
+}
+
+// assertErrorHasNoCredentials checks the error text and the endpoint of an *APIError, if any.
+func assertErrorHasNoCredentials(t *testing.T, err error, anonymous bool) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	assertNoCredentials(t, "error", err.Error(), anonymous)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		assertNoCredentials(t, "endpoint", apiErr.Endpoint, anonymous)
+	}
+}
+
+// testClients returns a credentialed and an anonymous client using the given base URL.
+func testClients(baseURL string, opts ...Option) map[string]*Client {
+	opts = append(opts, WithBaseURL(baseURL))
+
+	return map[string]*Client{
+		"credentialed": New(testUserID, testAPIKey, opts...),
+		"anonymous":    NewAnonymous(opts...),
+	}
+}
+
+func TestErrorsDoNotContainCredentials(t *testing.T) {
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refusedURL := testBaseURL(refused)
+	refused.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	cases := []struct {
+		name    string
+		baseURL string
+		opts    []Option
+	}{
+		{"refused connection", refusedURL, nil},
+		{"attempt timeout", testBaseURL(slow), []Option{WithTimeout(20 * time.Millisecond)}},
+		{"non-200 status", testBaseURL(failing), nil},
+	}
+
+	for _, tc := range cases {
+		for kind, c := range testClients(tc.baseURL, tc.opts...) {
+			t.Run(fmt.Sprintf("%s/%s", tc.name, kind), func(t *testing.T) {
+				anonymous := c.IsAnonymous()
+
+				_, err := c.Posts().Tags("cat_girl").Find()
+				assertErrorHasNoCredentials(t, err, anonymous)
+
+				_, err = c.Comments().PostID(1).Find()
+				assertErrorHasNoCredentials(t, err, anonymous)
+
+				_, err = c.Tags().Find()
+				assertErrorHasNoCredentials(t, err, anonymous)
+			})
+		}
+	}
+}
+
+func TestRedactedURLDoesNotContainCredentials(t *testing.T) {
+	for kind, c := range testClients(defaultBaseURL) {
+		t.Run(kind, func(t *testing.T) {
+			anonymous := c.IsAnonymous()
+
+			builders := map[string]interface {
+				fmt.Stringer
+				RedactedURL() (string, error)
+			}{
+				"posts":         c.Posts().Tags("cat_girl"),
+				"comments":      c.Comments().PostID(1),
+				"tags":          c.Tags(),
+				"deleted posts": c.DeletedPosts(),
+			}
+
+			for name, b := range builders {
+				u, err := b.RedactedURL()
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", name, err)
+				}
+
+				assertNoCredentials(t, name+" RedactedURL", u, anonymous)
+				assertNoCredentials(t, name+" String", b.String(), anonymous)
+
+				if !anonymous && !strings.Contains(u, "api_key="+redactedValue) {
+					t.Errorf("%s: redacted URL %s has no api_key placeholder", name, u)
+				}
+			}
+		})
+	}
+}
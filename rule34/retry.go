@@ -0,0 +1,100 @@
+package rule34
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how the client retries failed requests.
+// Transient network errors, 429 Too Many Requests and 5xx responses are retried
+// with exponential backoff and jitter, honoring the Retry-After header when present.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values less than or equal to one disable retries.
+	MaxAttempts int
+	// InitialBackoff is the base delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts, not counting Retry-After.
+	MaxBackoff time.Duration
+	// MaxElapsed caps the total time spent on a request including all retries.
+	// Zero means no limit.
+	MaxElapsed time.Duration
+}
+
+// NoRetryPolicy performs every request exactly once. It is the default policy of a new client.
+var NoRetryPolicy = RetryPolicy{
+	MaxAttempts: 1,
+}
+
+// DefaultRetryPolicy is a reasonable policy for long-running crawls.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+	MaxElapsed:     2 * time.Minute,
+}
+
+// SetRetryPolicy sets the policy used to retry failed requests of all builders of the client.
+// It isn't safe to call while requests are in flight; prefer WithRetryPolicy when creating the client.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retryPolicy = policy
+}
+
+// canRetry reports whether another attempt may be made after the given number of attempts.
+func (p RetryPolicy) canRetry(attempts int) bool {
+	return attempts < p.MaxAttempts
+}
+
+// backoff returns the delay before the next attempt. Retry-After takes precedence
+// over the computed exponential delay, which is jittered to avoid synchronized retries.
+func (p RetryPolicy) backoff(attempts int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	delay := p.InitialBackoff
+	for i := 1; i < attempts && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// isRetryableStatus reports whether a response with the given status code is worth retrying.
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// parseRetryAfter parses the Retry-After header, given either in seconds or as an HTTP date.
+// It returns zero if the header is missing or malformed.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,207 @@
+package rule34
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRetryPolicy retries quickly, so tests don't wait for the default backoff.
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+}
+
+// failingServer answers the first failures requests with the given status and Retry-After seconds,
+// if any, and every later request with an empty JSON list. It counts the requests it receives.
+func failingServer(t *testing.T, failures int, status int, retryAfter int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(attempts.Add(1)) <= failures {
+			if retryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &attempts
+}
+
+func TestDoRequestRetriesRetryableStatus(t *testing.T) {
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusBadGateway} {
+		server, attempts := failingServer(t, 2, status, 0)
+
+		c := New("1", "key", WithRetryPolicy(testRetryPolicy))
+		if _, err := c.doRequest(context.Background(), testBaseURL(server)); err != nil {
+			t.Errorf("%d: unexpected error: %v", status, err)
+		}
+
+		if got := attempts.Load(); got != 3 {
+			t.Errorf("%d: attempts = %d, want 3", status, got)
+		}
+	}
+}
+
+func TestDoRequestGivesUpAfterMaxAttempts(t *testing.T) {
+	server, attempts := failingServer(t, 10, http.StatusServiceUnavailable, 0)
+
+	policy := testRetryPolicy
+	policy.MaxAttempts = 3
+
+	c := New("1", "key", WithRetryPolicy(policy))
+	_, err := c.doRequest(context.Background(), testBaseURL(server))
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("error = %v, want an *APIError with status 503", err)
+	}
+
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestDoRequestDoesNotRetryPermanentStatus(t *testing.T) {
+	server, attempts := failingServer(t, 10, http.StatusNotFound, 0)
+
+	c := New("1", "key", WithRetryPolicy(testRetryPolicy))
+	_, err := c.doRequest(context.Background(), testBaseURL(server))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("error = %v, want it to match ErrNotFound", err)
+	}
+
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestDoRequestHonorsRetryAfter(t *testing.T) {
+	server, attempts := failingServer(t, 1, http.StatusTooManyRequests, 1)
+
+	c := New("1", "key", WithRetryPolicy(testRetryPolicy))
+
+	start := time.Now()
+	if _, err := c.doRequest(context.Background(), testBaseURL(server)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s requested by Retry-After", elapsed)
+	}
+
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestDoRequestStopsAtMaxElapsed(t *testing.T) {
+	server, attempts := failingServer(t, 10, http.StatusServiceUnavailable, 60)
+
+	policy := testRetryPolicy
+	policy.MaxElapsed = time.Second
+
+	c := New("1", "key", WithRetryPolicy(policy))
+
+	start := time.Now()
+	_, err := c.doRequest(context.Background(), testBaseURL(server))
+	if !errors.As(err, new(*APIError)) {
+		t.Errorf("error = %v, want an *APIError", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("gave up after %v, want right away since Retry-After exceeds MaxElapsed", elapsed)
+	}
+
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestDoRequestStopsWhenContextIsCanceled(t *testing.T) {
+	server, attempts := failingServer(t, 10, http.StatusServiceUnavailable, 60)
+
+	c := New("1", "key", WithRetryPolicy(testRetryPolicy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.doRequest(ctx, testBaseURL(server))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want it to match context.DeadlineExceeded", err)
+	}
+
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestDoRequestDoesNotRetryCanceledContext(t *testing.T) {
+	server, attempts := failingServer(t, 0, http.StatusOK, 0)
+
+	c := New("1", "key", WithRetryPolicy(testRetryPolicy))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.doRequest(ctx, testBaseURL(server)); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want it to match context.Canceled", err)
+	}
+
+	if got := attempts.Load(); got != 0 {
+		t.Errorf("attempts = %d, want 0", got)
+	}
+}
+
+// flakyTransport fails the first failures round trips with a network error.
+type flakyTransport struct {
+	failures int32
+	attempts atomic.Int32
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.attempts.Add(1) <= t.failures {
+		return nil, errors.New("connection reset by peer")
+	}
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestDoRequestRetriesTransportErrors(t *testing.T) {
+	server, _ := failingServer(t, 0, http.StatusOK, 0)
+	transport := &flakyTransport{failures: 2}
+
+	c := New("1", "key", WithTransport(transport), WithRetryPolicy(testRetryPolicy))
+	if _, err := c.doRequest(context.Background(), testBaseURL(server)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := transport.attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestBackoffPrefersRetryAfter(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: 2 * time.Second}
+
+	if got := policy.backoff(3, 10*time.Second); got != 10*time.Second {
+		t.Errorf("backoff = %v, want the 10s of Retry-After", got)
+	}
+
+	for attempts := 1; attempts < 5; attempts++ {
+		if got := policy.backoff(attempts, 0); got <= 0 || got > policy.MaxBackoff {
+			t.Errorf("backoff after %d attempts = %v, want within (0, %v]", attempts, got, policy.MaxBackoff)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package rule34
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SavedSearchVersion is the schema version written into the JSON form of PostsOptions.
+const SavedSearchVersion = 1
+
+// Pre-defined errors for saved searches.
+var (
+	// ErrUnsupportedSchemaVersion is returned when a saved search has a missing or unknown schema version.
+	ErrUnsupportedSchemaVersion = errors.New("unsupported saved search schema version")
+)
+
+// savedSearch is the stable JSON form of PostsOptions.
+type savedSearch struct {
+	Version      int              `json:"version"`
+	PostID       int              `json:"post_id,omitempty"`
+	Limit        int              `json:"limit,omitempty"`
+	PageNumber   int              `json:"page_number,omitempty"`
+	Tags         []string         `json:"tags,omitempty"`
+	BlackList    []string         `json:"blacklist,omitempty"`
+	TagPatterns  []string         `json:"tag_patterns,omitempty"`
+	OrGroups     [][]string       `json:"or_groups,omitempty"`
+	FilterAI     bool             `json:"filter_ai,omitempty"`
+	Rating       Rating           `json:"rating,omitempty"`
+	ParentPostID int              `json:"parent_post_id,omitempty"`
+	Conditions   []Condition      `json:"conditions,omitempty"`
+	Ranges       []RangeCondition `json:"ranges,omitempty"`
+	MD5          string           `json:"md5,omitempty"`
+	Source       string           `json:"source,omitempty"`
+	User         string           `json:"user,omitempty"`
+	Status       PostStatus       `json:"status,omitempty"`
+	Sort         *savedSort       `json:"sort,omitempty"`
+	MaxResults   int              `json:"max_results,omitempty"`
+	CursorPostID int              `json:"cursor_post_id,omitempty"`
+}
+
+// savedSort is the stable JSON form of the sort settings.
+type savedSort struct {
+	By    SortableType `json:"by"`
+	Order string       `json:"order,omitempty"`
+}
+
+// Options returns a copy of the builder's options, for example to save the search.
+func (b *PostsRequestBuilder) Options() PostsOptions {
+	return b.options.clone()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The options are written in a stable form carrying the SavedSearchVersion schema version.
+func (o PostsOptions) MarshalJSON() ([]byte, error) {
+	saved := savedSearch{
+		Version:      SavedSearchVersion,
+		PostID:       o.PostID,
+		Limit:        o.Limit,
+		PageNumber:   o.PageNumber,
+		Tags:         o.Tags,
+		BlackList:    o.BlackList,
+		TagPatterns:  o.TagPatterns,
+		OrGroups:     o.OrGroups,
+		FilterAI:     o.FilterAI,
+		Rating:       o.Rating,
+		ParentPostID: o.ParentPostID,
+		Conditions:   o.FilteringConditions,
+		Ranges:       o.RangeConditions,
+		MD5:          o.MD5,
+		Source:       o.Source,
+		User:         o.User,
+		Status:       o.Status,
+		MaxResults:   o.MaxResults,
+		CursorPostID: o.CursorPostID,
+	}
+
+	if o.DoSort {
+		saved.Sort = &savedSort{
+			By:    o.SortableType,
+			Order: o.SortingOrder,
+		}
+	}
+
+	return json.Marshal(saved)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It reads the form written by MarshalJSON and rejects unknown schema versions.
+// The values aren't validated; use Client.PostsFromOptions to get a validated builder.
+func (o *PostsOptions) UnmarshalJSON(data []byte) error {
+	var saved savedSearch
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	if saved.Version != SavedSearchVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedSchemaVersion, saved.Version)
+	}
+
+	*o = PostsOptions{
+		PostID:              saved.PostID,
+		Limit:               saved.Limit,
+		PageNumber:          saved.PageNumber,
+		Tags:                saved.Tags,
+		BlackList:           saved.BlackList,
+		TagPatterns:         saved.TagPatterns,
+		OrGroups:            saved.OrGroups,
+		FilterAI:            saved.FilterAI,
+		Rating:              saved.Rating,
+		ParentPostID:        saved.ParentPostID,
+		FilteringConditions: saved.Conditions,
+		RangeConditions:     saved.Ranges,
+		MD5:                 saved.MD5,
+		Source:              saved.Source,
+		User:                saved.User,
+		Status:              saved.Status,
+		MaxResults:          saved.MaxResults,
+		CursorPostID:        saved.CursorPostID,
+	}
+
+	if saved.Sort != nil {
+		o.DoSort = true
+		o.SortableType = saved.Sort.By
+		o.SortingOrder = saved.Sort.Order
+	}
+
+	return nil
+}
+
+// TagString returns the raw tag string of the options, such as "cat_girl -solo rating:safe sort:score:asc",
+// which ParseQuery turns back into options. It isn't a lossless encoding: the post ID, limit, page number
+// and max results aren't part of the tag string, and a cursor comes back as an id:< condition.
+// Use the JSON form to store a search completely.
+func (o PostsOptions) TagString() string {
+	b := PostsRequestBuilder{options: o}
+	return strings.TrimSpace(b.convertTags())
+}
+
+// PostsFromOptions returns a PostsRequestBuilder rebuilt from stored options.
+// Every option is replayed through the matching builder method, so invalid values are
+// recorded in the builder's errors and reported by Find just like for a hand-built request.
+func (c *Client) PostsFromOptions(o PostsOptions) *PostsRequestBuilder {
+	b := c.Posts()
+
+	if o.PostID != 0 {
+		b.PostID(o.PostID)
+	}
+
+	if o.Limit != 0 {
+		b.Limit(o.Limit)
+	}
+
+	if o.PageNumber != 0 {
+		b.PageNumber(o.PageNumber)
+	}
+
+	b.Tags(o.Tags...)
+	b.BlackList(o.BlackList...)
+
+	for _, pattern := range o.TagPatterns {
+		b.TagPattern(pattern)
+	}
+
+	for _, group := range o.OrGroups {
+		b.AnyOf(group...)
+	}
+
+	if o.FilterAI {
+		b.FilterAI()
+	}
+
+	if o.Rating != "" {
+		b.Rating(o.Rating)
+	}
+
+	if o.ParentPostID != 0 {
+		b.ParentPostID(o.ParentPostID)
+	}
+
+	for _, fc := range o.FilteringConditions {
+		b.Where(fc.FilteringType, fc.Operation, fc.Argument)
+	}
+
+	for _, rc := range o.RangeConditions {
+		b.WhereBetween(rc.FilteringType, rc.From, rc.To)
+	}
+
+	if o.MD5 != "" {
+		b.MD5(o.MD5)
+	}
+
+	if o.Source != "" {
+		b.Source(o.Source)
+	}
+
+	if o.User != "" {
+		b.User(o.User)
+	}
+
+	if o.Status != "" {
+		b.Status(o.Status)
+	}
+
+	if o.DoSort {
+		b.SortBy(o.SortableType)
+
+		switch o.SortingOrder {
+		case "":
+		case "asc":
+			b.Asc()
+		case "desc":
+			b.Desc()
+		default:
+			b.errors = append(b.errors, fmt.Errorf("%w: %q", ErrUnknownSortingOrder, o.SortingOrder))
+		}
+	}
+
+	if o.MaxResults != 0 {
+		b.MaxResults(o.MaxResults)
+	}
+
+	if o.CursorPostID != 0 {
+		b.After(newCursor(o.CursorPostID))
+	}
+
+	return b
+}
+
+// LoadSearch rebuilds a validated PostsRequestBuilder from a search saved as JSON.
+// It returns an error if the JSON is malformed, has an unknown schema version, or holds invalid options.
+func (c *Client) LoadSearch(data []byte) (*PostsRequestBuilder, error) {
+	var o PostsOptions
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("can't unmarshal saved search: %w", err)
+	}
+
+	b := c.PostsFromOptions(o)
+	if len(b.errors) != 0 {
+		return nil, fmt.Errorf("invalid saved search: %w", errors.Join(b.errors...))
+	}
+
+	return b, nil
+}
@@ -0,0 +1,286 @@
+package rule34
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Pre-defined errors for the TagsRequestBuilder.
+var (
+	// ErrNonPositiveTagID is returned when a non-positive tag ID is provided.
+	ErrNonPositiveTagID = errors.New("tag id can't be less than or equal to zero")
+	// ErrNonPositiveAfterID is returned when a non-positive after ID is provided.
+	ErrNonPositiveAfterID = errors.New("after id can't be less than or equal to zero")
+	// ErrEmptyTagName is returned when an empty tag name or pattern is provided.
+	ErrEmptyTagName = errors.New("tag name can't be empty")
+	// ErrUnknownTagSortingType is returned when an invalid tag sorting type is provided.
+	ErrUnknownTagSortingType = errors.New("unknown tag sorting type was given")
+	// ErrTagOrderByWasNotCalled is returned when Asc() or Desc() is called before OrderBy().
+	ErrTagOrderByWasNotCalled = errors.New("order by was not called")
+	// ErrTagOrderByWasCalledTwiceOrMore is returned when OrderBy() is called multiple times on the same builder.
+	ErrTagOrderByWasCalledTwiceOrMore = errors.New("order by was called twice or more")
+)
+
+// TagsRequestBuilder is a builder for creating and executing API requests for tags.
+type TagsRequestBuilder struct {
+	options TagsOptions
+	client  *Client
+	errors  []error
+}
+
+// TagsOptions holds all the configurable parameters for a tags API request.
+type TagsOptions struct {
+	ID          int
+	AfterID     int
+	Limit       int
+	PageNumber  int
+	Name        string
+	Names       []string
+	NamePattern string
+	DoOrder     bool
+	OrderBy     TagSortableType
+	Order       string
+}
+
+// ID sets the specific tag ID to retrieve.
+func (b *TagsRequestBuilder) ID(id int) *TagsRequestBuilder {
+	if id <= 0 {
+		b.errors = append(b.errors, ErrNonPositiveTagID)
+		return b
+	}
+
+	b.options.ID = id
+	return b
+}
+
+// AfterID retrieves only tags with an ID greater than the given one.
+func (b *TagsRequestBuilder) AfterID(afterID int) *TagsRequestBuilder {
+	if afterID <= 0 {
+		b.errors = append(b.errors, ErrNonPositiveAfterID)
+		return b
+	}
+
+	b.options.AfterID = afterID
+	return b
+}
+
+// Limit sets the maximum number of tags to retrieve.
+func (b *TagsRequestBuilder) Limit(limit int) *TagsRequestBuilder {
+	if limit <= 0 {
+		b.errors = append(b.errors, ErrNonPositiveLimit)
+		return b
+	}
+
+	b.options.Limit = limit
+	return b
+}
+
+// PageNumber sets the page number for pagination.
+func (b *TagsRequestBuilder) PageNumber(pageNumber int) *TagsRequestBuilder {
+	if pageNumber <= 0 {
+		b.errors = append(b.errors, ErrNonPositivePageNumber)
+		return b
+	}
+
+	b.options.PageNumber = pageNumber
+	return b
+}
+
+// Name sets the exact name of the tag to retrieve.
+// The name is normalized the same way as post tags, so "long hair" becomes "long_hair".
+func (b *TagsRequestBuilder) Name(name string) *TagsRequestBuilder {
+	name = normalizeTag(name)
+	if name == "" {
+		b.errors = append(b.errors, ErrEmptyTagName)
+		return b
+	}
+
+	b.options.Name = name
+	return b
+}
+
+// Names adds several exact tag names to retrieve.
+// Multiple calls to this method will append names. The names are normalized like in Name,
+// so a name containing spaces is sent as a single tag rather than split into several.
+func (b *TagsRequestBuilder) Names(names ...string) *TagsRequestBuilder {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = normalizeTag(name)
+		if name == "" {
+			b.errors = append(b.errors, ErrEmptyTagName)
+			return b
+		}
+		normalized = append(normalized, name)
+	}
+
+	b.options.Names = append(b.options.Names, normalized...)
+	return b
+}
+
+// NamePattern sets an SQL LIKE pattern the tag names must match.
+// For example, NamePattern("cat%") finds all tags starting with "cat".
+func (b *TagsRequestBuilder) NamePattern(pattern string) *TagsRequestBuilder {
+	if pattern == "" {
+		b.errors = append(b.errors, ErrEmptyTagName)
+		return b
+	}
+
+	b.options.NamePattern = pattern
+	return b
+}
+
+// OrderBy specifies the field to order the results by.
+// Must be called before Asc() or Desc().
+func (b *TagsRequestBuilder) OrderBy(sortableType TagSortableType) *TagsRequestBuilder {
+	if b.options.DoOrder {
+		b.errors = append(b.errors, ErrTagOrderByWasCalledTwiceOrMore)
+		return b
+	}
+
+	if !sortableType.IsValid() {
+		b.errors = append(b.errors, ErrUnknownTagSortingType)
+		return b
+	}
+
+	b.options.DoOrder = true
+	b.options.OrderBy = sortableType
+	return b
+}
+
+// Asc sets the ordering to ascending.
+// Must be called after OrderBy().
+func (b *TagsRequestBuilder) Asc() *TagsRequestBuilder {
+	return b.setOrder("asc")
+}
+
+// Desc sets the ordering to descending.
+// Must be called after OrderBy().
+func (b *TagsRequestBuilder) Desc() *TagsRequestBuilder {
+	return b.setOrder("desc")
+}
+
+// Find executes the request to the API with a background context. See FindContext.
+func (b *TagsRequestBuilder) Find() ([]Tag, error) {
+	return b.FindContext(context.Background())
+}
+
+// FindContext executes the request to the API and returns the found tags.
+// It first validates any accumulated errors, then builds the URL, performs the request, and unmarshals the response.
+func (b *TagsRequestBuilder) FindContext(ctx context.Context) ([]Tag, error) {
+	if len(b.errors) != 0 {
+		err := errors.Join(b.errors...)
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	url, err := b.buildURL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build url: %w", err)
+	}
+
+	tagsBytes, err := b.client.doRequest(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to do get tags request: %w", err)
+	}
+
+	tags, err := unmarshalTags(tagsBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// buildURL constructs the final request URL from the builder's options.
+func (b *TagsRequestBuilder) buildURL() (string, error) {
+	u, err := url.Parse(b.client.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("can't parse base URL: %w", err)
+	}
+
+	q := u.Query()
+	b.addOptions(&q)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// addOptions adds all configured options as query parameters to the URL.
+func (b *TagsRequestBuilder) addOptions(q *url.Values) {
+	q.Set("s", "tag")
+
+	id := strconv.Itoa(b.options.ID)
+	if id != "0" {
+		q.Set("id", id)
+	}
+
+	afterID := strconv.Itoa(b.options.AfterID)
+	if afterID != "0" {
+		q.Set("after_id", afterID)
+	}
+
+	limit := strconv.Itoa(b.options.Limit)
+	if limit != "0" {
+		q.Set("limit", limit)
+	}
+
+	pageNumber := strconv.Itoa(b.options.PageNumber)
+	if pageNumber != "0" {
+		q.Set("pid", pageNumber)
+	}
+
+	if b.options.Name != "" {
+		q.Set("name", b.options.Name)
+	}
+
+	if len(b.options.Names) != 0 {
+		q.Set("names", strings.Join(b.options.Names, " "))
+	}
+
+	if b.options.NamePattern != "" {
+		q.Set("name_pattern", b.options.NamePattern)
+	}
+
+	if b.options.DoOrder {
+		q.Set("orderby", string(b.options.OrderBy))
+		if b.options.Order != "" {
+			q.Set("order", b.options.Order)
+		}
+	}
+}
+
+// setOrder records the ordering direction after checking that OrderBy was called exactly once before.
+func (b *TagsRequestBuilder) setOrder(order string) *TagsRequestBuilder {
+	if !b.options.DoOrder {
+		b.errors = append(b.errors, ErrTagOrderByWasNotCalled)
+		return b
+	}
+
+	if b.options.Order != "" {
+		b.errors = append(b.errors, ErrTwoSortingOrders)
+		return b
+	}
+
+	b.options.Order = order
+	return b
+}
+
+// unmarshalTags parses the XML response body into a slice of Tag models.
+func unmarshalTags(tagsBytes []byte) ([]Tag, error) {
+	if len(tagsBytes) == 0 {
+		return []Tag{}, nil
+	}
+
+	var tags Tags
+
+	err := xml.Unmarshal(tagsBytes, &tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+
+	return tags.Tag, nil
+}
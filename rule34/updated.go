@@ -0,0 +1,65 @@
+package rule34
+
+import (
+	"errors"
+	"time"
+)
+
+// Pre-defined errors for the updated time filters.
+var (
+	// ErrZeroTime is returned when a zero time is given to an updated time filter.
+	ErrZeroTime = errors.New("time can't be zero")
+	// ErrInvalidTimeRange is returned when the start of a time range is after its end.
+	ErrInvalidTimeRange = errors.New("time range start can't be after its end")
+	// ErrNonPositiveDuration is returned when a non-positive duration is given to UpdatedWithin.
+	ErrNonPositiveDuration = errors.New("duration can't be less than or equal to zero")
+)
+
+// UpdatedAfter finds posts last updated after the given time.
+// The API compares the updated field as a Unix timestamp with second precision.
+func (b *PostsRequestBuilder) UpdatedAfter(t time.Time) *PostsRequestBuilder {
+	if t.IsZero() {
+		b.errors = append(b.errors, ErrZeroTime)
+		return b
+	}
+
+	return b.Where(FilterByUpdated, Greater, int(t.Unix()))
+}
+
+// UpdatedBefore finds posts last updated before the given time.
+func (b *PostsRequestBuilder) UpdatedBefore(t time.Time) *PostsRequestBuilder {
+	if t.IsZero() {
+		b.errors = append(b.errors, ErrZeroTime)
+		return b
+	}
+
+	return b.Where(FilterByUpdated, Less, int(t.Unix()))
+}
+
+// UpdatedBetween finds posts last updated within the given time range, both ends included.
+func (b *PostsRequestBuilder) UpdatedBetween(from, to time.Time) *PostsRequestBuilder {
+	if from.IsZero() || to.IsZero() {
+		b.errors = append(b.errors, ErrZeroTime)
+		return b
+	}
+
+	if from.After(to) {
+		b.errors = append(b.errors, ErrInvalidTimeRange)
+		return b
+	}
+
+	return b.
+		Where(FilterByUpdated, GreaterEqual, int(from.Unix())).
+		Where(FilterByUpdated, LessEqual, int(to.Unix()))
+}
+
+// UpdatedWithin finds posts last updated within the given duration before now.
+// The current time is taken when the method is called, not when the request is executed.
+func (b *PostsRequestBuilder) UpdatedWithin(d time.Duration) *PostsRequestBuilder {
+	if d <= 0 {
+		b.errors = append(b.errors, ErrNonPositiveDuration)
+		return b
+	}
+
+	return b.Where(FilterByUpdated, GreaterEqual, int(time.Now().Add(-d).Unix()))
+}
@@ -0,0 +1,39 @@
+package rule34
+
+import (
+	"context"
+	"net/http/httptrace"
+	"time"
+)
+
+// VerifyResult describes the outcome of a credential verification.
+type VerifyResult struct {
+	Reachable     bool          // Whether the API answered at all
+	Authenticated bool          // Whether the API accepted the credentials, always false for anonymous clients
+	Latency       time.Duration // Time the verification request took, including retries
+}
+
+// Verify makes a minimal authenticated request for a single post and reports whether the API
+// is reachable, whether it accepts the credentials, and how long the request took.
+// The API counts as reachable as soon as it sends any response, even one that fails to decode.
+// The returned error is nil only if the request succeeded. Anonymous clients send no credentials,
+// so they are never reported as authenticated.
+func (c *Client) Verify(ctx context.Context) (VerifyResult, error) {
+	var result VerifyResult
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotFirstResponseByte: func() {
+			result.Reachable = true
+		},
+	})
+
+	start := time.Now()
+	_, err := c.Posts().Limit(1).FindContext(ctx)
+	result.Latency = time.Since(start)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Authenticated = !c.IsAnonymous()
+	return result, nil
+}
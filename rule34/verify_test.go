@@ -0,0 +1,45 @@
+package rule34
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	ok, _ := failingServer(t, 0, http.StatusOK, 0)
+	failing, _ := failingServer(t, 10, http.StatusInternalServerError, 0)
+	garbled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":`))
+	}))
+	defer garbled.Close()
+
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refusedURL := testBaseURL(refused)
+	refused.Close()
+
+	tests := []struct {
+		name    string
+		client  *Client
+		want    VerifyResult
+		wantErr bool
+	}{
+		{"authenticated", New("1", "key", WithBaseURL(testBaseURL(ok))), VerifyResult{Reachable: true, Authenticated: true}, false},
+		{"anonymous", NewAnonymous(WithBaseURL(testBaseURL(ok))), VerifyResult{Reachable: true}, false},
+		{"error status", New("1", "key", WithBaseURL(testBaseURL(failing))), VerifyResult{Reachable: true}, true},
+		{"undecodable body", New("1", "key", WithBaseURL(testBaseURL(garbled))), VerifyResult{Reachable: true}, true},
+		{"refused connection", New("1", "key", WithBaseURL(refusedURL)), VerifyResult{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.client.Verify(context.Background())
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+
+		if got.Reachable != tt.want.Reachable || got.Authenticated != tt.want.Authenticated {
+			t.Errorf("%s: result = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}